@@ -0,0 +1,164 @@
+package hatch
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const adoptUsage = "usage: hatch adopt <path> [name] [--link]"
+
+type adoptOptions struct {
+	link bool
+}
+
+func newAdoptFlagSet(options *adoptOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("adopt", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&options.link, "link", false, "symlink the directory into the hatchery instead of moving it")
+	return fs
+}
+
+// birthTimeFn is replaced in tests, where every directory is brand new.
+var birthTimeFn = birthTime
+
+// earliestAdoptedDate is older than any real project. Earlier times, such
+// as the Unix epoch or the 1980 that zip archives and reproducible builds
+// stamp files with, are placeholders.
+var earliestAdoptedDate = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// adoptedDate picks the date for an adopted directory: when it was
+// created, or else the oldest modification time in it. Build artifacts and
+// .git are skipped since package managers and clones stamp files with
+// arbitrary times, and so are times before earliestAdoptedDate or after
+// now. It returns the zero time when nothing plausible is found.
+func adoptedDate(path string, now time.Time) time.Time {
+	plausible := func(t time.Time) bool {
+		return !t.Before(earliestAdoptedDate) && !t.After(now)
+	}
+	if created, ok := birthTimeFn(path); ok && plausible(created) {
+		return created
+	}
+	var oldest time.Time
+	_ = filepath.WalkDir(path, func(entryPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && entryPath != path {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			for _, rule := range artifactRules {
+				if rule.matches(entryPath) {
+					return fs.SkipDir
+				}
+			}
+		}
+		if info, err := d.Info(); err == nil && plausible(info.ModTime()) && (oldest.IsZero() || info.ModTime().Before(oldest)) {
+			oldest = info.ModTime()
+		}
+		return nil
+	})
+	return oldest
+}
+
+// adoptProject brings the directory at source into the hatchery as name,
+// dated by adoptedDate, and records where it came from. With link set the
+// directory stays put and the hatchery gets a symlink to it.
+func adoptProject(ctx context.Context, root, source, name string, link bool, now time.Time, opts moveOptions) (string, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return "", fmt.Errorf("adopt: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("adopt: %s is not a directory", source)
+	}
+	if inside(source, root) || inside(root, source) {
+		return "", fmt.Errorf("adopt: %s overlaps the hatchery %s", source, root)
+	}
+	if name == "" {
+		name = undatedName(filepath.Base(source))
+	}
+	date := adoptedDate(source, now)
+	if date.IsZero() {
+		date = now
+	}
+	dirName, err := projectDirName(name, date.Local())
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", fmt.Errorf("create hatchery root: %w", err)
+	}
+
+	target := filepath.Join(root, dirName)
+	if link {
+		err = withHatcheryLock(root, func() error {
+			if _, err := os.Lstat(target); err == nil {
+				return &projectExistsError{Path: target}
+			}
+			if err := os.Symlink(source, target); err != nil {
+				return fmt.Errorf("link project: %w", err)
+			}
+			return nil
+		})
+	} else {
+		err = relocateDir(ctx, root, source, target, opts)
+	}
+	if err != nil {
+		return "", err
+	}
+	return target, updateMetadata(root, func(md *metadata) error {
+		meta := md.project(target)
+		meta.AdoptedFrom = source
+		meta.AdoptedAt = now
+		md.setProject(target, meta)
+		return nil
+	})
+}
+
+// runAdopt brings an existing directory into the hatchery:
+// hatch adopt <path> [name] [--link].
+func runAdopt(c commandContext, args []string) error {
+	var options adoptOptions
+	args, err := parseCommandFlags(newAdoptFlagSet(&options), args, adoptUsage)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(adoptUsage)
+	}
+	source, err := expandPath(args[0])
+	if err != nil {
+		return err
+	}
+	name := ""
+	if len(args) == 2 {
+		name = args[1]
+	}
+	progress := newTerminalProgress(c.errOut, time.Now)
+	opts := moveOptions{progress: progress.copy, warn: func(message string) {
+		progress.finish()
+		fmt.Fprintln(c.errOut, "warning: "+message)
+	}}
+	target, err := adoptProject(context.Background(), c.root, source, name, options.link, c.now(), opts)
+	progress.finish()
+	if err != nil {
+		return err
+	}
+	verb := "Adopted "
+	if options.link {
+		verb = "Linked "
+	}
+	fmt.Fprintln(c.out, successStyle().Render(verb+source+" as "+filepath.Base(target)))
+	return nil
+}
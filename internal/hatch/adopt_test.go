@@ -0,0 +1,124 @@
+package hatch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAdoptedDateIgnoresImplausibleTimes(t *testing.T) {
+	birthTimeFn = func(string) (time.Time, bool) { return time.Unix(0, 0), true }
+	t.Cleanup(func() { birthTimeFn = birthTime })
+
+	source := t.TempDir()
+	writeTree(t, source, map[string]string{"epoch": "", "zip": "", "future": "", "real": ""})
+	times := map[string]time.Time{
+		"epoch":  time.Unix(0, 0),
+		"zip":    time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC),
+		"future": fixedNow().Add(24 * time.Hour),
+		"real":   time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC),
+		".":      fixedNow().Add(time.Hour),
+	}
+	for name, mtime := range times {
+		if err := os.Chtimes(filepath.Join(source, name), mtime, mtime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	if got := adoptedDate(source, fixedNow()); !got.Equal(times["real"]) {
+		t.Fatalf("adoptedDate = %s, want %s", got, times["real"])
+	}
+}
+
+func TestRunAdopt(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	t.Setenv("HATCH_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	birthTimeFn = func(string) (time.Time, bool) { return time.Time{}, false }
+	t.Cleanup(func() { birthTimeFn = birthTime })
+
+	source := filepath.Join(t.TempDir(), "old-tool")
+	writeTree(t, source, map[string]string{
+		"main.go":                   "package main",
+		"package.json":              "{}",
+		"node_modules/dep/index.js": "",
+	})
+	old := time.Date(2024, 5, 6, 12, 0, 0, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(source, "main.go"), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	// Artifacts are skipped: an ancient file in node_modules must not win.
+	ancient := time.Date(2001, 1, 1, 0, 0, 0, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(source, "node_modules", "dep", "index.js"), ancient, ancient); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"adopt", source}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("adopt: %v", err)
+	}
+	target := filepath.Join(root, "2024-05-06-old-tool")
+	if _, err := os.Stat(filepath.Join(target, "main.go")); err != nil {
+		t.Fatalf("expected adopted project: %v", err)
+	}
+	if _, err := os.Stat(source); !os.IsNotExist(err) {
+		t.Fatalf("expected source to be moved, got %v", err)
+	}
+	if !strings.Contains(out.String(), "as 2024-05-06-old-tool") {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+	md, err := loadMetadata(root)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta := md.project(target); meta.AdoptedFrom != source || !meta.AdoptedAt.Equal(fixedNow()) {
+		t.Fatalf("unexpected provenance %+v", meta)
+	}
+
+	if err := run([]string{"adopt", filepath.Join(target, "node_modules")}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err == nil {
+		t.Fatal("expected error adopting a directory inside the hatchery")
+	}
+}
+
+func TestRunAdoptLink(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	t.Setenv("HATCH_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	birthTimeFn = func(string) (time.Time, bool) { return time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local), true }
+	t.Cleanup(func() { birthTimeFn = birthTime })
+
+	source := filepath.Join(t.TempDir(), "site")
+	writeTree(t, source, map[string]string{"index.html": "", "src/app.js": ""})
+
+	if err := run([]string{"adopt", "--link", source, "Blog"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("adopt: %v", err)
+	}
+	link := filepath.Join(root, "2025-03-04-blog")
+	if dest, err := os.Readlink(link); err != nil || dest != source {
+		t.Fatalf("Readlink = %q, %v; want %q", dest, err, source)
+	}
+	if _, err := os.Stat(filepath.Join(source, "index.html")); err != nil {
+		t.Fatalf("expected source to stay put: %v", err)
+	}
+
+	projects, err := listProjects(root)
+	if err != nil {
+		t.Fatalf("listProjects: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Path != link {
+		t.Fatalf("expected linked project to be listed, got %+v", projects)
+	}
+	project, err := projectContaining(root, filepath.Join(source, "src"))
+	if err != nil || project.Path != link {
+		t.Fatalf("projectContaining = %+v, %v; want %s", project, err, link)
+	}
+
+	if err := run([]string{"promote", "blog", t.TempDir()}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil || !strings.Contains(err.Error(), "outside the hatchery") {
+		t.Fatalf("expected promote to refuse a linked project, got %v", err)
+	}
+	if err := run([]string{"adopt", "--link", source, "blog"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil {
+		t.Fatal("expected error when the dated name is taken")
+	}
+}
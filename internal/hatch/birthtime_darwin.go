@@ -0,0 +1,22 @@
+//go:build darwin
+
+package hatch
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// birthTime returns when path was created.
+func birthTime(path string) (time.Time, bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Birthtimespec.Unix()), true
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package hatch
+
+import "time"
+
+// birthTime is unknown on this platform.
+func birthTime(path string) (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package hatch
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// birthTime returns when path was created.
+func birthTime(path string) (time.Time, bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, data.CreationTime.Nanoseconds()), true
+}
@@ -0,0 +1,242 @@
+package hatch
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// targets are the projects a browser action applies to: the marked ones
+// the filter shows, in list order, or the selected project when nothing is
+// marked. Marked projects hidden by the filter are left alone.
+func (m browserModel) targets() []Project {
+	if len(m.marked) == 0 {
+		if selected := m.currentProject(); selected != nil {
+			return []Project{*selected}
+		}
+		return nil
+	}
+	var projects []Project
+	for _, index := range m.filtered {
+		if project := m.projects[index]; m.marked[project.Path] {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
+
+// hiddenNote tells a confirmation how many marked projects the filter hides
+// and so leaves out, or returns "" when it hides none.
+func (m browserModel) hiddenNote() string {
+	hidden := len(m.marked) - len(m.targets())
+	if len(m.marked) == 0 || hidden == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d marked %s hidden by the filter %s left out.", hidden, plural(hidden, "project", "projects"), plural(hidden, "is", "are"))
+}
+
+// targetSize sums the sizes of projects, reporting false until every one of
+// them has been scanned.
+func (m browserModel) targetSize(projects []Project) (int64, bool) {
+	var total int64
+	for _, project := range projects {
+		stats, ok := m.stats[project.Path]
+		if !ok {
+			return 0, false
+		}
+		total += stats.Size
+	}
+	return total, true
+}
+
+// describeTargets names a single project or counts several, such as
+// "2026-02-28-api" or "3 projects".
+func describeTargets(projects []Project) string {
+	if len(projects) == 1 {
+		return projects[0].Name
+	}
+	return fmt.Sprintf("%d projects", len(projects))
+}
+
+// linkNote tells a delete confirmation that symlinked projects lose only
+// their link, or returns "" when none of projects is a link.
+func linkNote(projects []Project) string {
+	var links []Project
+	for _, project := range projects {
+		if project.Target != "" {
+			links = append(links, project)
+		}
+	}
+	switch {
+	case len(links) == 0:
+		return ""
+	case len(links) == 1:
+		return fmt.Sprintf("Only the link is removed; %s is kept.", links[0].Target)
+	default:
+		return fmt.Sprintf("%d symlinked projects lose only their link; their targets are kept.", len(links))
+	}
+}
+
+// pinnedNote warns a delete or archive confirmation about pinned targets,
+// or returns "" when none of projects is pinned.
+func (m browserModel) pinnedNote(projects []Project) string {
+	var pinned []Project
+	for _, project := range projects {
+		if m.projectMeta(project.Path).Pinned {
+			pinned = append(pinned, project)
+		}
+	}
+	switch {
+	case len(pinned) == 0:
+		return ""
+	case len(pinned) == 1:
+		return fmt.Sprintf("%s is pinned.", pinned[0].Name)
+	default:
+		return fmt.Sprintf("%d of them are pinned.", len(pinned))
+	}
+}
+
+// bulkFailure is a project a bulk action could not handle.
+type bulkFailure struct {
+	project Project
+	err     error
+}
+
+// applyEach runs apply on every project, carrying on past failures, and
+// returns a status line such as "Deleted 3 projects" or "Deleted 2 of 3
+// projects; 2026-02-28-api: permission denied".
+func applyEach(verb string, projects []Project, apply func(Project) error) (string, []bulkFailure) {
+	var failures []bulkFailure
+	for _, project := range projects {
+		if err := apply(project); err != nil {
+			failures = append(failures, bulkFailure{project: project, err: err})
+		}
+	}
+	if len(failures) == 0 {
+		return fmt.Sprintf("%s %s", verb, describeTargets(projects)), nil
+	}
+	reasons := make([]string, len(failures))
+	for i, failure := range failures {
+		reasons[i] = failure.project.Name + ": " + failure.err.Error()
+	}
+	status := fmt.Sprintf("%s %d of %d projects; %s", verb, len(projects)-len(failures), len(projects), strings.Join(reasons, "; "))
+	if len(projects) == 1 {
+		status = strings.Join(reasons, "; ")
+	}
+	return status, failures
+}
+
+// keptMarks are the marks left after a bulk action on targets: the projects
+// it failed on, so it can be retried, and any marked projects the filter
+// hid from it.
+func (m browserModel) keptMarks(targets []Project, failures []bulkFailure) map[string]bool {
+	kept := maps.Clone(m.marked)
+	for _, project := range targets {
+		delete(kept, project.Path)
+	}
+	if kept == nil {
+		kept = make(map[string]bool, len(failures))
+	}
+	for _, failure := range failures {
+		kept[failure.project.Path] = true
+	}
+	return kept
+}
+
+// forgetProject drops the metadata of a project that has left the
+// hatchery.
+func forgetProject(root, path string) error {
+	return updateMetadata(root, func(md *metadata) error {
+		md.setProject(path, projectMeta{})
+		return nil
+	})
+}
+
+// startBulk deletes, archives, or moves the targets in the background,
+// one at a time, and reports each failure once all of them are done.
+// Projects that fail stay marked so the action can be retried.
+func (m browserModel) startBulk(targets []Project) (tea.Model, tea.Cmd) {
+	var verb, progress, suffix string
+	var apply func(context.Context, Project) error
+	var warnings []string
+	switch m.action {
+	case actionDeleteConfirm:
+		verb, progress = "Deleted", "Deleting"
+		apply = func(_ context.Context, project Project) error {
+			if err := removeProject(filepath.Dir(project.Path), project.Path); err != nil {
+				return err
+			}
+			return forgetProject(filepath.Dir(project.Path), project.Path)
+		}
+	case actionArchiveConfirm:
+		verb, progress = "Archived", "Archiving"
+		apply = func(_ context.Context, project Project) error {
+			if _, err := archiveProject(filepath.Dir(project.Path), project.Path); err != nil {
+				return err
+			}
+			return forgetProject(filepath.Dir(project.Path), project.Path)
+		}
+	case actionMoveInput:
+		dest, err := expandPath(strings.TrimSpace(m.promptInput))
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		verb, progress, suffix = "Moved", "Moving", " to "+dest
+		apply = func(ctx context.Context, project Project) error {
+			opts := moveOptions{warn: func(message string) { warnings = append(warnings, message) }}
+			if _, err := moveProjectTo(ctx, filepath.Dir(project.Path), project.Path, dest, opts); err != nil {
+				return err
+			}
+			return forgetProject(filepath.Dir(project.Path), project.Path)
+		}
+	}
+
+	label := fmt.Sprintf("%s %s", progress, describeTargets(targets))
+	return m.runTask(label, func(ctx context.Context, report func(string)) taskResult {
+		done := 0
+		status, failures := applyEach(verb, targets, func(project Project) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			done++
+			report(fmt.Sprintf("%s (%d/%d)", project.Name, done, len(targets)))
+			return apply(ctx, project)
+		})
+		if err := ctx.Err(); err != nil {
+			return taskResult{err: err}
+		}
+		if len(failures) == 0 {
+			status += suffix
+		}
+		if len(warnings) > 0 {
+			status += "; " + strings.Join(warnings, "; ")
+		}
+		return taskResult{status: status, marked: m.keptMarks(targets, failures)}
+	})
+}
+
+// applyTags edits the tags of the targets. Projects that fail stay marked
+// so the edit can be retried.
+func (m browserModel) applyTags(targets []Project) browserModel {
+	edits := strings.FieldsFunc(m.promptInput, func(r rune) bool { return r == ' ' || r == ',' })
+	status, failures := applyEach("Tagged", targets, func(project Project) error {
+		return updateMetadata(filepath.Dir(project.Path), func(md *metadata) error {
+			meta := md.project(project.Path)
+			tags, err := editTags(meta.Tags, edits)
+			if err != nil {
+				return err
+			}
+			meta.Tags = tags
+			md.setProject(project.Path, meta)
+			return nil
+		})
+	})
+	m.marked = m.keptMarks(targets, failures)
+	m.status = status
+	return m
+}
@@ -0,0 +1,331 @@
+package hatch
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// artifactRule describes a directory a build tool regenerates. It only
+// counts as an artifact when one of its markers is present, so a hand-made
+// "build" or "dist" directory is left alone.
+type artifactRule struct {
+	dir string
+	// markers are files expected next to dir.
+	markers []string
+	// inside are files expected in dir itself.
+	inside []string
+}
+
+// artifactRules lists the build artifacts hatch clean removes. A rule
+// without markers always matches.
+var artifactRules = []artifactRule{
+	{dir: "node_modules", markers: []string{"package.json"}},
+	{dir: "target", markers: []string{"Cargo.toml", "pom.xml"}},
+	{dir: "build", markers: []string{"build.gradle", "build.gradle.kts", "CMakeLists.txt", "setup.py", "pyproject.toml", "package.json"}},
+	{dir: "dist", markers: []string{"package.json", "setup.py", "pyproject.toml"}},
+	{dir: ".venv", inside: []string{"pyvenv.cfg"}},
+	{dir: "__pycache__"},
+	{dir: ".gradle", markers: []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}},
+}
+
+func (r artifactRule) matches(path string) bool {
+	if filepath.Base(path) != r.dir {
+		return false
+	}
+	if len(r.markers) == 0 && len(r.inside) == 0 {
+		return true
+	}
+	for _, marker := range r.markers {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(path), marker)); err == nil {
+			return true
+		}
+	}
+	for _, marker := range r.inside {
+		if _, err := os.Stat(filepath.Join(path, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+type artifact struct {
+	path string
+	size int64
+}
+
+// cleanPlan is what hatch clean would remove from one project.
+type cleanPlan struct {
+	project   Project
+	artifacts []artifact
+	// modified is the newest modification time outside the artifacts, so
+	// reinstalling dependencies does not make a project look active.
+	modified time.Time
+}
+
+func (p cleanPlan) size() int64 {
+	var total int64
+	for _, a := range p.artifacts {
+		total += a.size
+	}
+	return total
+}
+
+// planClean finds the artifact directories in the project, looking in the
+// target of a symlinked project. It does not follow symlinks below that and
+// never looks inside .git. A directory git tracks files in is source, such
+// as a committed build/webpack.config.js, and is kept.
+func planClean(project Project) cleanPlan {
+	plan := cleanPlan{project: project}
+	dir := project.dir()
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && path != dir {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			for _, rule := range artifactRules {
+				if rule.matches(path) && !gitTracksAny(path) {
+					plan.artifacts = append(plan.artifacts, artifact{path: path, size: walkProjectStats(path).Size})
+					return fs.SkipDir
+				}
+			}
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(plan.modified) {
+			plan.modified = info.ModTime()
+		}
+		return nil
+	})
+	return plan
+}
+
+// gitTracksAny reports whether git tracks any file under dir. Outside a
+// repository nothing is tracked. Inside one, a git that is missing or fails
+// counts as tracking something, so dir is kept rather than guessed at.
+func gitTracksAny(dir string) bool {
+	files, err := gitTrackedFilesFn(context.Background(), dir)
+	return err != nil || len(files) > 0
+}
+
+// parseAge reads ages such as "14d", "2w", or any time.ParseDuration value.
+func parseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age %q", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age %q (use 14d, 2w, or 36h)", value)
+	}
+	return age, nil
+}
+
+const cleanUsage = "usage: hatch clean [project...] [--all] [--older-than 14d] [--gc] [--yes] [--dry-run]"
+
+type cleanOptions struct {
+	all       bool
+	olderThan string
+	gc        bool
+	yes       bool
+	dryRun    bool
+}
+
+func newCleanFlagSet(options *cleanOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&options.all, "all", false, "clean every project except pinned ones")
+	fs.StringVar(&options.olderThan, "older-than", "", "only projects not modified for this long, such as 14d")
+	fs.BoolVar(&options.gc, "gc", false, "also run git gc in cloned projects")
+	fs.BoolVar(&options.yes, "yes", false, "do not ask for confirmation")
+	fs.BoolVar(&options.yes, "y", false, "shorthand for --yes")
+	fs.BoolVar(&options.dryRun, "dry-run", false, "only show what would be removed")
+	return fs
+}
+
+var gitGCFn = func(dir string) error {
+	output, err := runGitCommand(context.Background(), nil, "-C", dir, "gc", "--quiet")
+	if err != nil {
+		return gitCommandError("git gc", output, err)
+	}
+	return nil
+}
+
+// runClean removes build artifacts from projects to reclaim space without
+// deleting the projects themselves.
+func runClean(c commandContext, args []string) error {
+	var options cleanOptions
+	fs := newCleanFlagSet(&options)
+	args, err := parseCommandFlags(fs, args, cleanUsage)
+	if err != nil {
+		return err
+	}
+	if options.all && len(args) > 0 {
+		return fmt.Errorf("--all does not take projects\n%s", cleanUsage)
+	}
+	var maxAge time.Duration
+	if options.olderThan != "" {
+		age, err := parseAge(options.olderThan)
+		if err != nil {
+			return fmt.Errorf("--older-than: %w", err)
+		}
+		maxAge = age
+	}
+
+	projects, skipped, err := cleanTargets(c, options.all, args)
+	if err != nil {
+		return err
+	}
+
+	now := c.now()
+	var plans []cleanPlan
+	var clones []Project
+	for _, project := range projects {
+		plan := planClean(project)
+		if maxAge > 0 && now.Sub(plan.modified) < maxAge {
+			continue
+		}
+		if len(plan.artifacts) > 0 {
+			plans = append(plans, plan)
+		}
+		if info, err := os.Stat(filepath.Join(project.Path, ".git")); options.gc && err == nil && info.IsDir() {
+			clones = append(clones, project)
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(c.out, "Skipping %d pinned %s\n", skipped, plural(skipped, "project", "projects"))
+	}
+	if len(plans) == 0 && len(clones) == 0 {
+		fmt.Fprintln(c.out, "Nothing to clean")
+		return nil
+	}
+
+	var total int64
+	var count int
+	for _, plan := range plans {
+		fmt.Fprintf(c.out, "%s (modified %s)\n", plan.project.Name, formatAge(plan.modified, now))
+		for _, a := range plan.artifacts {
+			rel, _ := filepath.Rel(plan.project.dir(), a.path)
+			fmt.Fprintf(c.out, "  %10s  %s\n", formatBytes(a.size), rel)
+		}
+		total += plan.size()
+		count += len(plan.artifacts)
+	}
+	if len(plans) > 0 {
+		fmt.Fprintf(c.out, "Reclaimable: %s in %d %s across %d %s\n", formatBytes(total),
+			count, plural(count, "directory", "directories"), len(plans), plural(len(plans), "project", "projects"))
+	}
+	if len(clones) > 0 {
+		fmt.Fprintf(c.out, "git gc: %d %s\n", len(clones), plural(len(clones), "clone", "clones"))
+	}
+	if options.dryRun {
+		return nil
+	}
+	if !options.yes && !confirm(c.in, c.out, "Proceed? [y/N] ") {
+		fmt.Fprintln(c.out, "Cancelled")
+		return nil
+	}
+
+	var failures []string
+	var reclaimed int64
+	for _, plan := range plans {
+		for _, a := range plan.artifacts {
+			if err := os.RemoveAll(a.path); err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+			reclaimed += a.size
+		}
+	}
+	for _, clone := range clones {
+		gitDir := filepath.Join(clone.Path, ".git")
+		before := walkProjectStats(gitDir).Size
+		if err := gitGCFn(clone.Path); err != nil {
+			failures = append(failures, clone.Name+": "+err.Error())
+			continue
+		}
+		reclaimed += max(0, before-walkProjectStats(gitDir).Size)
+	}
+
+	fmt.Fprintln(c.out, successStyle().Render("Reclaimed "+formatBytes(reclaimed)))
+	for _, failure := range failures {
+		fmt.Fprintln(c.errOut, "warning: "+failure)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("clean: %d %s failed", len(failures), plural(len(failures), "removal", "removals"))
+	}
+	return nil
+}
+
+// cleanTargets resolves the projects to clean. --all leaves out pinned
+// projects and reports how many it skipped.
+func cleanTargets(c commandContext, all bool, refs []string) ([]Project, int, error) {
+	if !all {
+		if len(refs) == 0 {
+			project, err := c.resolveProject("")
+			return []Project{project}, 0, err
+		}
+		var projects []Project
+		for _, ref := range refs {
+			project, err := c.resolveProject(ref)
+			if err != nil {
+				return nil, 0, err
+			}
+			projects = append(projects, project)
+		}
+		return projects, 0, nil
+	}
+	listed, err := listProjects(c.root)
+	if err != nil {
+		return nil, 0, err
+	}
+	md, err := loadMetadata(c.root)
+	if err != nil {
+		return nil, 0, err
+	}
+	var projects []Project
+	skipped := 0
+	for _, project := range listed {
+		if md.project(project.Path).Pinned {
+			skipped++
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects, skipped, nil
+}
+
+// confirm asks question and reports whether the answer was yes. No answer,
+// such as stdin at EOF, counts as no.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprint(out, question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,211 @@
+package hatch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlanCleanUsesMarkers(t *testing.T) {
+	requireGit(t)
+	t.Parallel()
+
+	root := t.TempDir()
+	runGit(t, root, "init", "--quiet")
+	writeTree(t, root, map[string]string{
+		"package.json":                   "{}",
+		"node_modules/left-pad/index.js": "module.exports = 1",
+		"web/package.json":               "{}",
+		"web/dist/app.js":                "x",
+		"docs/build/notes.txt":           "hand-written, no marker",
+		"rust/Cargo.toml":                "",
+		"rust/target/debug/app":          "bin",
+		"py/.venv/pyvenv.cfg":            "",
+		"py/.venv/lib/site.py":           "",
+		"py/pkg/__pycache__/m.pyc":       "",
+		"notpy/.venv/random":             "",
+		".git/node_modules/x":            "",
+	})
+
+	plan := planClean(Project{Name: "p", Path: root})
+	var got []string
+	for _, a := range plan.artifacts {
+		rel, _ := filepath.Rel(root, a.path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	want := "node_modules,py/.venv,py/pkg/__pycache__,rust/target,web/dist"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("artifacts = %v, want %s", got, want)
+	}
+	if plan.size() != int64(len("module.exports = 1")+len("x")+len("bin")) {
+		t.Fatalf("size = %d", plan.size())
+	}
+}
+
+func TestPlanCleanKeepsTrackedDirectories(t *testing.T) {
+	requireGit(t)
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"package.json":              "{}",
+		"build/webpack.config.js":   "module.exports = {}",
+		"dist/bundle.js":            "generated",
+		"node_modules/dep/index.js": "",
+	})
+	runGit(t, root, "init", "--quiet")
+	runGit(t, root, "add", "package.json", "build/webpack.config.js")
+	runGit(t, root, "commit", "--quiet", "-m", "init")
+
+	plan := planClean(Project{Name: "p", Path: root})
+	var got []string
+	for _, a := range plan.artifacts {
+		rel, _ := filepath.Rel(root, a.path)
+		got = append(got, filepath.ToSlash(rel))
+	}
+	if want := "dist,node_modules"; strings.Join(got, ",") != want {
+		t.Fatalf("artifacts = %v, want %s", got, want)
+	}
+}
+
+func TestPlanCleanKeepsArtifactsWhenGitFails(t *testing.T) {
+	repo := t.TempDir()
+	writeTree(t, repo, map[string]string{"package.json": "{}", "node_modules/dep/index.js": ""})
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatalf("create .git: %v", err)
+	}
+	plain := t.TempDir()
+	writeTree(t, plain, map[string]string{"package.json": "{}", "node_modules/dep/index.js": ""})
+	// Without git on PATH nobody can say what the repository tracks.
+	t.Setenv("PATH", "")
+
+	if plan := planClean(Project{Name: "repo", Path: repo}); len(plan.artifacts) != 0 {
+		t.Fatalf("expected artifacts in a repository to be kept without git, got %v", plan.artifacts)
+	}
+	if plan := planClean(Project{Name: "plain", Path: plain}); len(plan.artifacts) != 1 {
+		t.Fatalf("expected artifacts outside a repository to be planned, got %v", plan.artifacts)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]time.Duration{"14d": 14 * 24 * time.Hour, "2w": 14 * 24 * time.Hour, "36h": 36 * time.Hour}
+	for input, want := range tests {
+		if got, err := parseAge(input); err != nil || got != want {
+			t.Fatalf("parseAge(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "d", "-3d", "soon"} {
+		if _, err := parseAge(input); err == nil {
+			t.Fatalf("expected error for %q", input)
+		}
+	}
+}
+
+func TestRunCleanAllOlderThan(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	old := fixedNow().Add(-30 * 24 * time.Hour)
+	for _, name := range []string{"2026-01-01-old", "2026-01-02-pinned", "2026-02-27-fresh"} {
+		project := filepath.Join(root, name)
+		writeTree(t, project, map[string]string{"package.json": "{}", "node_modules/dep/index.js": "12345"})
+		if name != "2026-02-27-fresh" {
+			for _, path := range []string{filepath.Join(project, "package.json"), project} {
+				if err := os.Chtimes(path, old, old); err != nil {
+					t.Fatalf("chtimes: %v", err)
+				}
+			}
+		}
+	}
+	if err := setProjectPinned(root, filepath.Join(root, "2026-01-02-pinned"), true); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+
+	clean := func(input string, args ...string) string {
+		t.Helper()
+		out := new(bytes.Buffer)
+		if err := run(append([]string{"clean"}, args...), strings.NewReader(input), out, new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("clean returned error: %v", err)
+		}
+		return out.String()
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(root, name, "node_modules"))
+		return err == nil
+	}
+
+	out := clean("", "--all", "--older-than", "14d", "--dry-run")
+	if !strings.Contains(out, "Skipping 1 pinned project") || !strings.Contains(out, "2026-01-01-old (modified 30d ago)") ||
+		!strings.Contains(out, "Reclaimable: 5 B in 1 directory across 1 project") || strings.Contains(out, "fresh") {
+		t.Fatalf("unexpected plan:\n%s", out)
+	}
+	if !exists("2026-01-01-old") {
+		t.Fatal("dry run removed artifacts")
+	}
+
+	if out := clean("n\n", "--all", "--older-than", "14d"); !strings.Contains(out, "Cancelled") || !exists("2026-01-01-old") {
+		t.Fatalf("expected no to cancel:\n%s", out)
+	}
+	if out := clean("y\n", "--all", "--older-than", "14d"); !strings.Contains(out, "Reclaimed 5 B") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+	if exists("2026-01-01-old") || !exists("2026-01-02-pinned") || !exists("2026-02-27-fresh") {
+		t.Fatal("expected only the old unpinned project to be cleaned")
+	}
+	if _, err := os.Stat(filepath.Join(root, "2026-01-01-old", "package.json")); err != nil {
+		t.Fatalf("expected sources to stay: %v", err)
+	}
+}
+
+func TestRunCleanLinkedProject(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	site := filepath.Join(t.TempDir(), "site")
+	writeTree(t, site, map[string]string{"package.json": "{}", "node_modules/dep/index.js": "12345"})
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatalf("create hatchery: %v", err)
+	}
+	if err := os.Symlink(site, filepath.Join(root, "2026-02-28-site")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"clean", "--yes", "site"}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("clean returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "5 B  node_modules") || !strings.Contains(out.String(), "Reclaimed 5 B") {
+		t.Fatalf("expected the link target's artifacts to be cleaned:\n%s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(site, "node_modules")); !os.IsNotExist(err) {
+		t.Fatalf("expected node_modules to be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(site, "package.json")); err != nil {
+		t.Fatalf("expected sources to stay: %v", err)
+	}
+}
+
+func TestRunCleanRunsGitGC(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	writeTree(t, filepath.Join(root, "2026-02-28-clone", ".git"), map[string]string{"HEAD": "ref: refs/heads/main\n"})
+	writeTree(t, filepath.Join(root, "2026-02-28-worktree"), map[string]string{".git": "gitdir: elsewhere\n"})
+
+	var collected []string
+	previous := gitGCFn
+	gitGCFn = func(dir string) error {
+		collected = append(collected, filepath.Base(dir))
+		return nil
+	}
+	t.Cleanup(func() { gitGCFn = previous })
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"clean", "--gc", "--yes", "clone", "worktree"}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("clean returned error: %v", err)
+	}
+	if strings.Join(collected, ",") != "2026-02-28-clone" || !strings.Contains(out.String(), "git gc: 1 clone") {
+		t.Fatalf("gc ran in %v:\n%s", collected, out.String())
+	}
+}
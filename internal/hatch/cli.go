@@ -1,11 +1,13 @@
 package hatch
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"time"
@@ -18,13 +20,244 @@ var cloneProjectFn = cloneProject
 var createProjectFn = createProject
 var copyProjectFn = copyProject
 var worktreeProjectFn = worktreeProject
+var pullRequestProjectFn = pullRequestProject
 
 type cliOptions struct {
-	cwdFile string
-	init    string
-	showVer bool
-	showUse bool
-	forceCP bool
+	cwdFile     string
+	hookVersion int
+	hookShell   string
+	complete    bool
+	literal     bool
+	init        string
+	completions string
+	showVer     bool
+	showUse     bool
+	forceCP     bool
+	ignoreFrom  stringList
+	exclude     stringList
+	noIgnore    bool
+	strategy    string
+	hardlink    bool
+	preserve    bool
+	onExists    string
+	edit        bool
+	tmux        bool
+	zellij      bool
+	space       string
+}
+
+// stringList collects a repeatable flag; comma-separated values are split.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// copyOptions merges copy flags over the config defaults: --ignore-from
+// replaces the configured ignore files, --exclude adds to the configured
+// patterns, --copy-strategy or --hardlink replace the configured strategy,
+// and --preserve turns on preserve mode.
+func (o cliOptions) copyOptions(cfg config) (copyOptions, error) {
+	opts, err := cfg.copyOptions()
+	if err != nil {
+		return opts, err
+	}
+	if len(o.ignoreFrom) > 0 {
+		opts.ignore.files = o.ignoreFrom
+	}
+	opts.ignore.exclude = append(append([]string{}, opts.ignore.exclude...), o.exclude...)
+	opts.ignore.disabled = o.noIgnore
+	if o.strategy != "" {
+		if opts.strategy, err = parseCopyStrategy(o.strategy); err != nil {
+			return opts, err
+		}
+	}
+	if o.hardlink {
+		opts.strategy = strategyHardlink
+	}
+	opts.preserve = opts.preserve || o.preserve
+	return opts, nil
+}
+
+// existsPolicy returns --on-exists, falling back to the configured policy.
+func (o cliOptions) existsPolicy(cfg config) (existsPolicy, error) {
+	if o.onExists != "" {
+		return parseExistsPolicy(o.onExists)
+	}
+	return cfg.existsPolicy()
+}
+
+// session returns the multiplexer to open the project in, or "" for none.
+// The browser's session key uses the configured multiplexer.
+func (o cliOptions) session(cfg config, browserKey bool) (multiplexer, error) {
+	switch {
+	case o.tmux && o.zellij:
+		return "", errors.New("use either --tmux or --zellij")
+	case o.tmux:
+		return multiplexerTmux, nil
+	case o.zellij:
+		return multiplexerZellij, nil
+	case browserKey:
+		program, err := parseMultiplexer(cfg.Session.Multiplexer)
+		if err != nil {
+			return "", fmt.Errorf("config session.multiplexer: %w", err)
+		}
+		return program, nil
+	}
+	return "", nil
+}
+
+// openExisting reports the existing project path when err says the target
+// was taken and the policy is to open it instead.
+func openExisting(onExists existsPolicy, err error) (string, bool) {
+	var exists *projectExistsError
+	if onExists == existsOpen && errors.As(err, &exists) {
+		return exists.Path, true
+	}
+	return "", false
+}
+
+// commandContext is what run hands to the code handling a command line.
+type commandContext struct {
+	root        string
+	cfg         config
+	options     cliOptions
+	in          io.Reader
+	out, errOut io.Writer
+	now         func() time.Time
+}
+
+// command is a hatch subcommand. A project named like a command is created
+// with "hatch -- <name>".
+type command struct {
+	run func(c commandContext, args []string) error
+	// args says what each argument is, for completion: argProject or
+	// argDir. With variadic set the last kind repeats.
+	args     []string
+	variadic bool
+	// flags returns the command's own flag set. run parses the same set
+	// with parseCommandFlags, so flags may come anywhere among the
+	// arguments, and completion offers them.
+	flags func() *flag.FlagSet
+}
+
+// Argument kinds for command.args.
+const (
+	argProject = "project"
+	argDir     = "dir"
+)
+
+var commands = map[string]command{
+	"adopt": {run: runAdopt, args: []string{argDir}, flags: func() *flag.FlagSet { return newAdoptFlagSet(&adoptOptions{}) }},
+	"allow": {run: runAllow, args: []string{argProject}},
+	"clean": {run: runClean, args: []string{argProject}, variadic: true, flags: func() *flag.FlagSet { return newCleanFlagSet(&cleanOptions{}) }},
+	"deny":  {run: runDeny, args: []string{argProject}},
+	"du":    {run: runDu, args: []string{argProject}, variadic: true, flags: func() *flag.FlagSet { return newDuFlagSet(&duOptions{}) }},
+	"note":  {run: runNote, args: []string{argProject}},
+	"pin":   {run: runPin, args: []string{argProject}},
+	"promote": {run: runPromote, args: []string{argProject, argDir}, flags: func() *flag.FlagSet {
+		return newPromoteFlagSet(&promoteOptions{})
+	}},
+	"tag":   {run: runTag, args: []string{argProject}},
+	"unpin": {run: runUnpin, args: []string{argProject}},
+}
+
+// argKind returns what the argument at index is, or "" when completion has
+// nothing to offer for it.
+func (cmd command) argKind(index int) string {
+	switch {
+	case index < len(cmd.args):
+		return cmd.args[index]
+	case cmd.variadic && len(cmd.args) > 0:
+		return cmd.args[len(cmd.args)-1]
+	default:
+		return ""
+	}
+}
+
+// parseCommandFlags parses a command's flags, which may come before, after,
+// or between its arguments, and returns the arguments. Everything after
+// "--" is an argument.
+func parseCommandFlags(fs *flag.FlagSet, args []string, usage string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, errors.New(usage)
+			}
+			return nil, fmt.Errorf("%w\n%s", err, usage)
+		}
+		rest := fs.Args()
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// projectArg resolves the optional project argument of the named command,
+// defaulting to the project containing the working directory.
+func projectArg(c commandContext, name string, args []string) (Project, error) {
+	switch len(args) {
+	case 0:
+		return c.resolveProject("")
+	case 1:
+		return c.resolveProject(args[0])
+	default:
+		return Project{}, fmt.Errorf("usage: hatch %s [project]", name)
+	}
+}
+
+// enter hands projectPath to the shell hook, along with the project's
+// environment when enabled, and opens it in an editor and a tmux or zellij
+// session when asked.
+func (c commandContext) enter(projectPath, message string, selection browserSelection) error {
+	program, err := c.options.session(c.cfg, selection.Session)
+	if err != nil {
+		return err
+	}
+	var actions shellActions
+	if err := actions.cd(projectPath); err != nil {
+		return err
+	}
+	applied, warnings := c.addProjectEnv(&actions, projectPath)
+	if err := actions.write(c.options.cwdFile, c.options.hookVersion); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render(message))
+	if len(applied) > 0 {
+		fmt.Fprintln(c.out, "Env: "+strings.Join(applied, ", "))
+	}
+	if root := filepath.Dir(filepath.Clean(projectPath)); root == filepath.Clean(c.root) || c.isSpaceRoot(root) {
+		if err := recordVisit(root, projectPath, c.now()); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(c.errOut, "warning: "+warning)
+	}
+	if c.options.edit || selection.Edit {
+		if err := openInEditor(filepath.Dir(filepath.Clean(projectPath)), c.cfg, projectPath, c.in, c.out, c.errOut); err != nil {
+			return err
+		}
+	}
+	if program != "" {
+		return openSession(program, c.cfg.Session, projectPath, c.in, c.out, c.errOut)
+	}
+	return nil
 }
 
 func Main(args []string, in io.Reader, out, errOut io.Writer) int {
@@ -45,6 +278,10 @@ func run(args []string, in io.Reader, out, errOut io.Writer, now func() time.Tim
 		return err
 	}
 
+	if warning := hookWarning(options, os.Getenv); warning != "" {
+		defer fmt.Fprintln(errOut, warning)
+	}
+
 	if options.showVer {
 		fmt.Fprintf(out, "hatch %s\n", version)
 		return nil
@@ -64,68 +301,130 @@ func run(args []string, in io.Reader, out, errOut io.Writer, now func() time.Tim
 		return nil
 	}
 
-	root, err := hatcheryRoot()
+	if options.completions != "" {
+		script, err := completionScript(options.completions)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, script)
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if options.complete {
+		// A broken config should not break completion of everything else.
+		if err != nil {
+			cfg = config{}
+		}
+		root, err := spaceRoot(cfg, "")
+		if err != nil {
+			return err
+		}
+		for _, candidate := range completeArgs(root, cfg, remaining) {
+			fmt.Fprintln(out, candidate)
+		}
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
+	root, err := spaceRoot(cfg, options.space)
+	if err != nil {
+		return err
+	}
+
+	onExists, err := options.existsPolicy(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := commandContext{root: root, cfg: cfg, options: options, in: in, out: out, errOut: errOut, now: now}
+	if len(remaining) > 0 && !options.literal {
+		if cmd, ok := commands[remaining[0]]; ok {
+			return cmd.run(c, remaining[1:])
+		}
+	}
 	switch len(remaining) {
 	case 0:
-		selected, err := runBrowser(root, in, out)
+		selected, err := runBrowser(root, cfg, in, out)
 		if err != nil {
 			if errors.Is(err, errNoSelection) {
 				return nil
 			}
 			return err
 		}
-		if err := writeCWD(options.cwdFile, selected); err != nil {
-			return err
-		}
-		fmt.Fprintln(out, successStyle().Render("Opened: "+selected))
-		return nil
+		return c.enter(selected.Path, "Opened: "+selected.Path, selected)
 	case 1:
 		var (
 			projectPath string
 			action      string
 		)
-		if isGitURL(remaining[0]) {
-			projectPath, err = cloneProjectFn(root, remaining[0], now())
+		if existing, ok := existingProject(root, remaining[0]); ok {
+			// The project directory is taken by definition, so the exists
+			// policy decides: fail, open it, or create a suffixed sibling.
+			projectPath, err = createProjectDir(root, filepath.Base(existing), onExists)
+			action = "Created: "
+		} else if ref, ok := parsePullRequestRef(remaining[0]); ok {
+			projectPath, err = pullRequestProjectFn(ctx, root, ref, now(), onExists, errOut)
+			action = "Checked out PR into: "
+		} else if isGitURL(remaining[0]) {
+			projectPath, err = cloneProjectFn(ctx, root, remaining[0], now(), onExists, errOut)
 			action = "Cloned into: "
 		} else {
-			projectPath, err = createProjectFn(root, remaining[0], now())
+			projectPath, err = createProjectFn(root, remaining[0], now(), onExists)
 			action = "Created: "
 		}
-		if err != nil {
-			return err
+		if existing, ok := openExisting(onExists, err); ok {
+			projectPath, action, err = existing, "Opened existing: ", nil
 		}
-		if err := writeCWD(options.cwdFile, projectPath); err != nil {
+		if err != nil {
 			return err
 		}
-		fmt.Fprintln(out, successStyle().Render(action+projectPath))
-		return nil
+		return c.enter(projectPath, action+projectPath, browserSelection{})
 	case 2:
 		var (
 			projectPath string
+			result      copyResult
 			action      = "Copied into: "
 		)
+		copyOpts, err := options.copyOptions(cfg)
+		if err != nil {
+			return err
+		}
+		progress := newTerminalProgress(errOut, time.Now)
+		copyOpts.progress = progress.copy
 		if options.forceCP {
-			projectPath, err = copyProjectFn(root, remaining[0], remaining[1], now())
+			projectPath, result, err = copyProjectFn(ctx, root, remaining[0], remaining[1], now(), onExists, copyOpts)
 		} else {
-			projectPath, err = worktreeProjectFn(root, remaining[0], remaining[1], now())
+			projectPath, err = worktreeProjectFn(ctx, root, remaining[0], remaining[1], now(), onExists, errOut)
 			if errors.Is(err, errNotGitRepo) {
-				projectPath, err = copyProjectFn(root, remaining[0], remaining[1], now())
+				projectPath, result, err = copyProjectFn(ctx, root, remaining[0], remaining[1], now(), onExists, copyOpts)
 			} else {
 				action = "Worktree created: "
 			}
 		}
+		progress.finish()
+		if existing, ok := openExisting(onExists, err); ok {
+			projectPath, action, err = existing, "Opened existing: ", nil
+		}
 		if err != nil {
 			return err
 		}
-		if err := writeCWD(options.cwdFile, projectPath); err != nil {
-			return err
+		for _, skipped := range result.Skipped {
+			fmt.Fprintln(errOut, "skipped "+skipped.String())
 		}
-		fmt.Fprintln(out, successStyle().Render(action+projectPath))
-		return nil
+		if warning := result.sharedWarning(); warning != "" {
+			fmt.Fprintln(errOut, warning)
+		}
+		message := action + projectPath
+		if summary := result.summary(); summary != "" {
+			message += " (" + summary + ")"
+		}
+		return c.enter(projectPath, message, browserSelection{})
 	default:
 		return fmt.Errorf("invalid argument count (%d)\n\n%s", len(remaining), usageText)
 	}
@@ -134,15 +433,7 @@ func run(args []string, in io.Reader, out, errOut io.Writer, now func() time.Tim
 func parseArgs(args []string) (cliOptions, []string, string, error) {
 	var options cliOptions
 	usageText := usage()
-	fs := flag.NewFlagSet("hatch", flag.ContinueOnError)
-	fs.SetOutput(io.Discard)
-	fs.StringVar(&options.cwdFile, "cwd-file", "", "internal: write selected path to file")
-	fs.StringVar(&options.init, "init", "", "print shell hook for zsh, bash, or fish")
-	fs.BoolVar(&options.showVer, "version", false, "print version")
-	fs.BoolVar(&options.showUse, "usage", false, "show styled usage guide")
-	fs.BoolVar(&options.forceCP, "copy", false, "force copy behavior for <path> <name>")
-	fs.BoolVar(&options.forceCP, "c", false, "shorthand for --copy")
-	fs.Usage = func() {}
+	fs := newFlagSet(&options)
 
 	err := fs.Parse(args)
 	if err != nil {
@@ -152,7 +443,43 @@ func parseArgs(args []string) (cliOptions, []string, string, error) {
 		return options, nil, usageText, fmt.Errorf("parse flags: %w", err)
 	}
 
-	return options, fs.Args(), usageText, nil
+	// "hatch -- allow" creates a project named allow instead of running
+	// the allow command.
+	remaining := fs.Args()
+	if n := len(args) - len(remaining); n > 0 && args[n-1] == "--" {
+		options.literal = true
+	}
+	return options, remaining, usageText, nil
+}
+
+// newFlagSet defines hatch's flags on options. Completion walks the same set
+// so it never drifts from what parseArgs accepts.
+func newFlagSet(options *cliOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("hatch", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&options.cwdFile, "cwd-file", "", "internal: write selected path to file")
+	fs.IntVar(&options.hookVersion, "hook-version", 0, "internal: shell hook protocol version")
+	fs.StringVar(&options.hookShell, "hook-shell", "", "internal: shell the hook runs in")
+	fs.BoolVar(&options.complete, "complete", false, "internal: print completion candidates for the remaining words")
+	fs.StringVar(&options.init, "init", "", "print shell hook for zsh, bash, fish, nu, pwsh, elvish, or xonsh")
+	fs.StringVar(&options.completions, "completions", "", "print completion script for zsh, bash, or fish")
+	fs.BoolVar(&options.showVer, "version", false, "print version")
+	fs.BoolVar(&options.showUse, "usage", false, "show styled usage guide")
+	fs.BoolVar(&options.forceCP, "copy", false, "force copy behavior for <path> <name>")
+	fs.BoolVar(&options.forceCP, "c", false, "shorthand for --copy")
+	fs.Var(&options.ignoreFrom, "ignore-from", "skip paths matched by these per-directory ignore files when copying")
+	fs.Var(&options.exclude, "exclude", "skip paths matching this gitignore-style pattern when copying")
+	fs.BoolVar(&options.noIgnore, "no-ignore", false, "copy everything, ignoring .hatchignore and configured rules")
+	fs.StringVar(&options.strategy, "copy-strategy", "", "copy files with auto, reflink, copy-range, plain, or hardlink")
+	fs.BoolVar(&options.hardlink, "hardlink", false, "shorthand for --copy-strategy hardlink")
+	fs.BoolVar(&options.preserve, "preserve", false, "keep times, xattrs, hardlinks, and special files when copying")
+	fs.StringVar(&options.onExists, "on-exists", "", "when the project already exists: fail, suffix, or open")
+	fs.BoolVar(&options.edit, "edit", false, "also open the project in an editor")
+	fs.BoolVar(&options.tmux, "tmux", false, "create or attach a tmux session for the project")
+	fs.BoolVar(&options.zellij, "zellij", false, "create or attach a zellij session for the project")
+	fs.StringVar(&options.space, "space", "", "use the named hatchery space from config")
+	fs.Usage = func() {}
+	return fs
 }
 
 func usage() string {
@@ -163,34 +490,93 @@ func usage() string {
 		"  hatch <name>",
 		"      Create ~/hatchery/<yyyy-mm-dd>-<name> and enter it.",
 		"",
+		"  hatch <yyyy-mm-dd>-<name>",
+		"      Refer to an existing project (with --on-exists open to enter it); shell completion offers project names.",
+		"",
 		"  hatch <git-url>",
 		"      Clone ssh/https git URL into ~/hatchery/<yyyy-mm-dd>-<repo-name> and enter it.",
 		"",
+		"  hatch <pr-url> | hatch <repo>#<number>",
+		"      Check out a GitHub PR or GitLab MR into ~/hatchery/<yyyy-mm-dd>-<repo-name>-pr<number>.",
+		"      <repo> may be a git URL (cloned) or a local checkout (worktree).",
+		"",
 		"  hatch <path> <name>",
 		"      If <path> is a git repo, create a git worktree in ~/hatchery/<yyyy-mm-dd>-<name>.",
 		"      Otherwise copy <path> into ~/hatchery/<yyyy-mm-dd>-<name>.",
 		"      Use --copy or -c to always copy.",
+		"      Copies honor .hatchignore files; add --ignore-from .gitignore to skip",
+		"      build artifacts and --exclude <glob> for extra patterns. Tracked git",
+		"      files are always copied. Copies use reflinks where the filesystem",
+		"      supports them; see --copy-strategy. Add --preserve to keep timestamps,",
+		"      xattrs, and hardlinks.",
 		"",
 		"  hatch",
 		"      Open the interactive browser with live fuzzy filtering.",
 		"",
+		"Commands:",
+		"  hatch allow [project]  Apply the project's .hatch/env on entry (needs env.enabled)",
+		"  hatch deny [project]   Stop applying the project's .hatch/env",
+		"  hatch clean [project...] [--all] [--older-than 14d] [--gc] [--yes] [--dry-run]",
+		"                         Remove build artifacts such as node_modules and target",
+		"  hatch du [--sort size] [--json] [project...]",
+		"                         Show size, file count, and biggest directories of projects",
+		"  hatch promote [project] [dest] [--link]",
+		"                         Move the project out of the hatchery without its date",
+		"  hatch adopt <path> [name] [--link]",
+		"                         Move or link an existing directory into the hatchery",
+		"  hatch pin [project]    Keep the project at the top of the browser",
+		"  hatch unpin [project]  Undo hatch pin",
+		"  hatch tag <project> [+tag|-tag ...]",
+		"                         Add or remove tags, or list them without edits",
+		"  hatch note <project> [text]",
+		"                         Set, print, or (with \"\") clear the project's note",
+		"  Without a project, commands use the one containing the current directory.",
+		"  Use hatch -- <name> to create a project named like a command.",
+		"",
 		"Actions in browser:",
 		"  Enter     Open selected project or create from input",
 		"  Ctrl+R    Rename selected project",
-		"  Ctrl+W    Delete selected project",
+		"  Ctrl+W    Delete selected or marked projects",
+		"  Ctrl+X    Archive selected or marked projects into archive/",
+		"  Ctrl+O    Move selected or marked projects to another directory",
+		"  Ctrl+U    Promote selected project to a permanent location",
+		"  Tab       Mark or unmark selected project",
+		"  Ctrl+A    Mark all filtered projects (again to unmark)",
 		"  Ctrl+V    Duplicate selected project (asks for new name)",
 		"  Ctrl+G    Create git worktree from selected project (asks for new name)",
-		"  Esc       Exit without selecting",
+		"  Ctrl+E    Open selected project in an editor (and cd into it)",
+		"  Ctrl+T    Create or attach a tmux/zellij session for the selected project",
+		"  Ctrl+P    Pin or unpin selected project",
+		"  Ctrl+S    Cycle sort: date, last modified, size, name, frecency",
+		"  Ctrl+L    Edit tags of selected project (+tag/-tag for marked projects)",
+		"  Ctrl+N    Edit note of selected project",
+		"  Ctrl+B    Cycle through configured spaces, then all of them at once",
+		"  #tag      Type #tag in the filter to show only projects with that tag",
+		"  Esc       Clear marks, exit without selecting, or cancel a running copy/worktree",
 		"",
-		"Shell integration (required for automatic cd):",
+		"Clones, copies, and worktrees report progress on stderr.",
+		"",
+		"Shell integration (required for automatic cd, includes completions):",
 		"  eval \"$(hatch --init zsh)\"",
 		"",
 		"Options:",
-		"  --init <shell>   Print shell hook for zsh, bash, or fish",
-		"  --version        Print version",
-		"  --usage          Show styled usage guide",
-		"  --copy, -c       Force copy behavior for hatch <path> <name>",
-		"  --help           Show this help message",
+		"  --init <shell>        Print shell hook for zsh, bash, fish, nu, pwsh, elvish, or xonsh",
+		"  --completions <shell> Print only the completion script for zsh, bash, or fish",
+		"  --version             Print version",
+		"  --usage               Show styled usage guide",
+		"  --copy, -c            Force copy behavior for hatch <path> <name>",
+		"  --ignore-from <file>  Honor per-directory ignore files (e.g. .gitignore) when copying",
+		"  --exclude <glob>      Skip paths matching a gitignore-style pattern when copying",
+		"  --no-ignore           Copy everything, including .hatchignore matches",
+		"  --copy-strategy <s>   Copy files with auto, reflink, copy-range, plain, or hardlink",
+		"  --hardlink            Hardlink files instead of copying (shared with the source)",
+		"  --preserve            Keep times, xattrs, hardlinks, pipes, and devices when copying",
+		"  --edit                Also open the project in an editor ($VISUAL, code, zed, nvim, ...)",
+		"  --tmux                Create or attach a tmux session named after the project",
+		"  --zellij              Create or attach a zellij session named after the project",
+		"  --on-exists <policy>  When the project already exists: fail, suffix (-2, -3, ...), or open",
+		"  --space <name>        Use the named hatchery from the spaces config instead of ~/hatchery",
+		"  --help                Show this help message",
 	}
 	return strings.Join(copy, "\n") + "\n"
 }
@@ -225,6 +611,10 @@ func usageCard() string {
 		body.Render("    Clone ssh/https URL into ~/hatchery/<yyyy-mm-dd>-<repo-name>."),
 		"",
 		spacer,
+		body.Render("  " + command.Render("hatch <pr-url>")),
+		body.Render("    Check out a PR/MR on a pr-<number> branch; <repo>#<number> works too."),
+		"",
+		spacer,
 		body.Render("  " + command.Render("hatch <path> <name>")),
 		body.Render("    Create a worktree when <path> is git; otherwise copy."),
 		body.Render("    Add --copy or -c to force copy mode."),
@@ -250,20 +640,6 @@ func usageCard() string {
 	return card.Render(strings.Join(sections, "\n"))
 }
 
-func writeCWD(cwdFile, path string) error {
-	if strings.TrimSpace(cwdFile) == "" {
-		return nil
-	}
-	abs, err := filepath.Abs(path)
-	if err != nil {
-		return fmt.Errorf("resolve path for cwd file: %w", err)
-	}
-	if err := os.WriteFile(cwdFile, []byte(abs), 0o644); err != nil {
-		return fmt.Errorf("write cwd file: %w", err)
-	}
-	return nil
-}
-
 func successStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#0F766E"))
 }
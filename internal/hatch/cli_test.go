@@ -2,13 +2,71 @@ package hatch
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 )
 
+func TestMain(m *testing.M) {
+	// Shell script tests run the test binary as hatch itself.
+	if os.Getenv("HATCH_TEST_MAIN") == "1" {
+		os.Exit(Main(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+	// Keep a developer's own ~/.config/hatch out of the tests.
+	configHome, err := os.MkdirTemp("", "hatch-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", configHome)
+	os.Unsetenv("HATCH_CONFIG")
+	code := m.Run()
+	os.RemoveAll(configHome)
+	os.Exit(code)
+}
+
+// TestParseCommandFlags parses each command's advertised usage through the
+// flag set completion also uses, with flags before, between, and after its
+// arguments.
+func TestParseCommandFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		args    []string
+		want    []string
+		flags   map[string]string
+	}{
+		{"promote", []string{"api", "--link", "~/code", "--", "--odd"}, []string{"api", "~/code", "--odd"}, map[string]string{"link": "true"}},
+		{"adopt", []string{"./src", "--link", "scratch"}, []string{"./src", "scratch"}, map[string]string{"link": "true"}},
+		{"du", []string{"api", "--sort", "size", "web", "--json"}, []string{"api", "web"}, map[string]string{"sort": "size", "json": "true"}},
+		{"clean", []string{"api", "--dry-run", "--older-than", "14d"}, []string{"api"}, map[string]string{"dry-run": "true", "older-than": "14d"}},
+	}
+	for _, tt := range tests {
+		fs := commands[tt.command].flags()
+		args, err := parseCommandFlags(fs, tt.args, "usage: hatch "+tt.command)
+		if err != nil {
+			t.Fatalf("%s %q: %v", tt.command, tt.args, err)
+		}
+		if !reflect.DeepEqual(args, tt.want) {
+			t.Fatalf("%s %q args = %q, want %q", tt.command, tt.args, args, tt.want)
+		}
+		for name, want := range tt.flags {
+			if got := fs.Lookup(name).Value.String(); got != want {
+				t.Fatalf("%s %q --%s = %q, want %q", tt.command, tt.args, name, got, want)
+			}
+		}
+	}
+	if _, err := parseCommandFlags(newPromoteFlagSet(&promoteOptions{}), []string{"--nope"}, promoteUsage); err == nil || !strings.Contains(err.Error(), promoteUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
+
 func TestRunCreateWritesCWDFile(t *testing.T) {
 	root := filepath.Join(t.TempDir(), "hatchery")
 	t.Setenv("HATCHERY_HOME", root)
@@ -67,6 +125,142 @@ func TestRunCopy(t *testing.T) {
 	}
 }
 
+func TestRunCopyAppliesConfigAndExcludeFlags(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"copy": {"ignore_from": [".gitignore"]}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{
+		".gitignore":          "node_modules/\n",
+		"index.js":            "code",
+		"node_modules/dep.js": "dep",
+		"tmp/scratch.txt":     "scratch",
+	})
+
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	if err := run([]string{"--copy", "--exclude", "tmp", source, "slim"}, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+		t.Fatalf("run copy returned error: %v", err)
+	}
+
+	target := filepath.Join(root, "2026-02-28-slim")
+	if _, err := os.Stat(filepath.Join(target, "index.js")); err != nil {
+		t.Fatalf("expected index.js to be copied: %v", err)
+	}
+	for _, skipped := range []string{"node_modules", "tmp"} {
+		if _, err := os.Stat(filepath.Join(target, skipped)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be skipped, err=%v", skipped, err)
+		}
+	}
+	if !strings.Contains(errOut.String(), "Copying 2/2 files") {
+		t.Fatalf("expected copy progress on stderr, got %q", errOut.String())
+	}
+}
+
+func TestRunCopyReportsStrategy(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+
+	source := filepath.Join(root, "source")
+	writeTree(t, source, map[string]string{"README.md": "seed"})
+
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	if err := run([]string{"--copy", "--hardlink", source, "snapshot"}, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+		t.Fatalf("run copy returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "(hardlink)") {
+		t.Fatalf("expected strategy in output, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "share their content with the source") {
+		t.Fatalf("expected a warning about files shared with the source, got %q", errOut.String())
+	}
+	if info, err := os.Stat(filepath.Join(source, "README.md")); err != nil || runtime.GOOS != "windows" && info.Mode().Perm()&0o200 == 0 {
+		t.Fatalf("expected the source to stay writable, got %v, %v", info, err)
+	}
+
+	if err := run([]string{"--copy", "--copy-strategy", "bogus", source, "other"}, strings.NewReader(""), out, errOut, fixedNow); err == nil {
+		t.Fatalf("expected unknown copy strategy to fail")
+	}
+}
+
+func TestRunCopyPreserveReportsSkippedEntries(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+
+	original := copyProjectFn
+	copyProjectFn = func(_ context.Context, _, _, name string, _ time.Time, _ existsPolicy, opts copyOptions) (string, copyResult, error) {
+		if !opts.preserve {
+			t.Fatalf("expected --preserve to enable preserve mode")
+		}
+		return filepath.Join(root, name), copyResult{
+			Strategies: map[copyStrategy]int{strategyPlain: 1},
+			Skipped:    []copySkip{{Path: filepath.Join("run", "app.sock"), Reason: "socket cannot be copied"}},
+		}, nil
+	}
+	t.Cleanup(func() { copyProjectFn = original })
+
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	if err := run([]string{"--copy", "--preserve", "/tmp/source", "faithful"}, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+		t.Fatalf("run copy returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "(plain, 1 skipped)") {
+		t.Fatalf("expected skipped count in output, got %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "skipped run/app.sock: socket cannot be copied") {
+		t.Fatalf("expected skipped entry on stderr, got %q", errOut.String())
+	}
+}
+
+func TestRunOnExistsPolicies(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	existing := filepath.Join(root, "2026-02-28-spike")
+	if err := os.MkdirAll(existing, 0o755); err != nil {
+		t.Fatalf("create existing project: %v", err)
+	}
+
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	if err := run([]string{"spike"}, strings.NewReader(""), out, errOut, fixedNow); err == nil || !strings.Contains(err.Error(), "project already exists") {
+		t.Fatalf("default policy error = %v, want project already exists", err)
+	}
+
+	cwdFile := filepath.Join(t.TempDir(), "cwd")
+	if err := run([]string{"--cwd-file", cwdFile, "--on-exists", "open", "spike"}, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+		t.Fatalf("run --on-exists open returned error: %v", err)
+	}
+	if cwd, err := os.ReadFile(cwdFile); err != nil || string(cwd) != existing {
+		t.Fatalf("cwd file = %q, %v; want %q", cwd, err, existing)
+	}
+	if !strings.Contains(out.String(), "Opened existing: "+existing) {
+		t.Fatalf("expected open output, got %q", out.String())
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"on_exists": "suffix"}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+	if err := run([]string{"--cwd-file", cwdFile, "spike"}, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+		t.Fatalf("run with suffix config returned error: %v", err)
+	}
+	if cwd, err := os.ReadFile(cwdFile); err != nil || string(cwd) != existing+"-2" {
+		t.Fatalf("cwd file = %q, %v; want %q", cwd, err, existing+"-2")
+	}
+
+	if err := run([]string{"--on-exists", "replace", "spike"}, strings.NewReader(""), out, errOut, fixedNow); err == nil {
+		t.Fatalf("expected unknown --on-exists policy to fail")
+	}
+}
+
 func TestRunPathNameUsesWorktreeForGitRepo(t *testing.T) {
 	root := filepath.Join(t.TempDir(), "hatchery")
 	t.Setenv("HATCHERY_HOME", root)
@@ -74,7 +268,7 @@ func TestRunPathNameUsesWorktreeForGitRepo(t *testing.T) {
 	wantPath := filepath.Join(root, "2026-02-28-feature")
 	originalWorktree := worktreeProjectFn
 	originalCopy := copyProjectFn
-	worktreeProjectFn = func(gotRoot, source, name string, now time.Time) (string, error) {
+	worktreeProjectFn = func(_ context.Context, gotRoot, source, name string, now time.Time, _ existsPolicy, _ io.Writer) (string, error) {
 		if gotRoot != root {
 			t.Fatalf("worktree root = %q, want %q", gotRoot, root)
 		}
@@ -83,9 +277,9 @@ func TestRunPathNameUsesWorktreeForGitRepo(t *testing.T) {
 		}
 		return wantPath, nil
 	}
-	copyProjectFn = func(_, _, _ string, _ time.Time) (string, error) {
+	copyProjectFn = func(_ context.Context, _, _, _ string, _ time.Time, _ existsPolicy, _ copyOptions) (string, copyResult, error) {
 		t.Fatalf("copyProjectFn should not be called when worktree succeeds")
-		return "", nil
+		return "", copyResult{}, nil
 	}
 	t.Cleanup(func() {
 		worktreeProjectFn = originalWorktree
@@ -109,17 +303,17 @@ func TestRunPathNameFallsBackToCopyOnNonGit(t *testing.T) {
 	wantPath := filepath.Join(root, "2026-02-28-feature")
 	originalWorktree := worktreeProjectFn
 	originalCopy := copyProjectFn
-	worktreeProjectFn = func(_, _, _ string, _ time.Time) (string, error) {
+	worktreeProjectFn = func(_ context.Context, _, _, _ string, _ time.Time, _ existsPolicy, _ io.Writer) (string, error) {
 		return "", errNotGitRepo
 	}
-	copyProjectFn = func(gotRoot, source, name string, now time.Time) (string, error) {
+	copyProjectFn = func(_ context.Context, gotRoot, source, name string, now time.Time, _ existsPolicy, _ copyOptions) (string, copyResult, error) {
 		if gotRoot != root {
 			t.Fatalf("copy root = %q, want %q", gotRoot, root)
 		}
 		if source != "/tmp/not-git" || name != "feature" {
 			t.Fatalf("unexpected copy args source=%q name=%q", source, name)
 		}
-		return wantPath, nil
+		return wantPath, copyResult{}, nil
 	}
 	t.Cleanup(func() {
 		worktreeProjectFn = originalWorktree
@@ -143,12 +337,12 @@ func TestRunPathNameForceCopySkipsWorktree(t *testing.T) {
 	wantPath := filepath.Join(root, "2026-02-28-feature")
 	originalWorktree := worktreeProjectFn
 	originalCopy := copyProjectFn
-	worktreeProjectFn = func(_, _, _ string, _ time.Time) (string, error) {
+	worktreeProjectFn = func(_ context.Context, _, _, _ string, _ time.Time, _ existsPolicy, _ io.Writer) (string, error) {
 		t.Fatalf("worktreeProjectFn should not be called when --copy is set")
 		return "", nil
 	}
-	copyProjectFn = func(_, _, _ string, _ time.Time) (string, error) {
-		return wantPath, nil
+	copyProjectFn = func(_ context.Context, _, _, _ string, _ time.Time, _ existsPolicy, _ copyOptions) (string, copyResult, error) {
+		return wantPath, copyResult{}, nil
 	}
 	t.Cleanup(func() {
 		worktreeProjectFn = originalWorktree
@@ -172,12 +366,12 @@ func TestRunPathNameShortCopyFlagSkipsWorktree(t *testing.T) {
 	wantPath := filepath.Join(root, "2026-02-28-feature")
 	originalWorktree := worktreeProjectFn
 	originalCopy := copyProjectFn
-	worktreeProjectFn = func(_, _, _ string, _ time.Time) (string, error) {
+	worktreeProjectFn = func(_ context.Context, _, _, _ string, _ time.Time, _ existsPolicy, _ io.Writer) (string, error) {
 		t.Fatalf("worktreeProjectFn should not be called when -c is set")
 		return "", nil
 	}
-	copyProjectFn = func(_, _, _ string, _ time.Time) (string, error) {
-		return wantPath, nil
+	copyProjectFn = func(_ context.Context, _, _, _ string, _ time.Time, _ existsPolicy, _ copyOptions) (string, copyResult, error) {
+		return wantPath, copyResult{}, nil
 	}
 	t.Cleanup(func() {
 		worktreeProjectFn = originalWorktree
@@ -200,7 +394,7 @@ func TestRunCloneFromGitURL(t *testing.T) {
 
 	wantPath := filepath.Join(root, "2026-02-28-hatch")
 	originalClone := cloneProjectFn
-	cloneProjectFn = func(gotRoot, repoURL string, now time.Time) (string, error) {
+	cloneProjectFn = func(_ context.Context, gotRoot, repoURL string, now time.Time, _ existsPolicy, _ io.Writer) (string, error) {
 		if gotRoot != root {
 			t.Fatalf("clone root = %q, want %q", gotRoot, root)
 		}
@@ -228,6 +422,41 @@ func TestRunCloneFromGitURL(t *testing.T) {
 	}
 }
 
+func TestRunPullRequestURL(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+
+	wantPath := filepath.Join(root, "2026-02-28-hatch-pr42")
+	originalPullRequest := pullRequestProjectFn
+	originalClone := cloneProjectFn
+	pullRequestProjectFn = func(_ context.Context, gotRoot string, ref pullRequestRef, now time.Time, _ existsPolicy, _ io.Writer) (string, error) {
+		if gotRoot != root {
+			t.Fatalf("pull request root = %q, want %q", gotRoot, root)
+		}
+		if ref.Repo != "https://github.com/nayeemzen/hatch.git" || ref.Number != 42 {
+			t.Fatalf("unexpected pull request ref %+v", ref)
+		}
+		return wantPath, nil
+	}
+	cloneProjectFn = func(_ context.Context, _, _ string, _ time.Time, _ existsPolicy, _ io.Writer) (string, error) {
+		t.Fatalf("cloneProjectFn should not be called for a pull request URL")
+		return "", nil
+	}
+	t.Cleanup(func() {
+		pullRequestProjectFn = originalPullRequest
+		cloneProjectFn = originalClone
+	})
+
+	out := new(bytes.Buffer)
+	errOut := new(bytes.Buffer)
+	if err := run([]string{"https://github.com/nayeemzen/hatch/pull/42"}, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Checked out PR into: "+wantPath) {
+		t.Fatalf("expected pull request output, got %q", out.String())
+	}
+}
+
 func TestRunBrowseWritesSelection(t *testing.T) {
 	root := filepath.Join(t.TempDir(), "hatchery")
 	t.Setenv("HATCHERY_HOME", root)
@@ -301,3 +530,15 @@ func TestUsageFlagOutput(t *testing.T) {
 		t.Fatalf("usage output missing copy override guidance: %q", content)
 	}
 }
+
+func TestRunDoubleDashCreatesProjectNamedLikeCommand(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+
+	if err := run([]string{"--", "allow"}, strings.NewReader(""), io.Discard, io.Discard, fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2026-02-28-allow")); err != nil {
+		t.Fatalf("expected project named allow: %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package hatch
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Completion directives lead the candidates printed by --complete and tell
+// the shell script what to add on top of them.
+const (
+	completeWords = ":words"
+	completeFiles = ":files"
+	completeDirs  = ":dirs"
+)
+
+// hiddenFlags are internal plumbing for the shell scripts and never offered.
+var hiddenFlags = map[string]bool{"cwd-file": true, "hook-version": true, "hook-shell": true, "complete": true}
+
+// completionScript returns the completion script for shell. The scripts
+// stay thin: they pass the words typed so far to hatch --complete and
+// offer whatever it prints.
+func completionScript(shell string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(shell)) {
+	case "zsh":
+		return zshCompletion(), nil
+	case "bash":
+		return bashCompletion(), nil
+	case "fish":
+		return fishCompletion(), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use zsh, bash, or fish)", shell)
+	}
+}
+
+// completeArgs returns completion candidates for words, the arguments after
+// "hatch" up to and including the word being completed. The first line is
+// a directive: completeFiles or completeDirs ask the shell to add paths,
+// completeWords offers only the candidates that follow.
+func completeArgs(root string, cfg config, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+	fs := newFlagSet(&cliOptions{})
+
+	// Walk the finished words to find out whether current is a flag value
+	// and, if not, which positional argument it is.
+	var positional []string
+	var pending *flag.Flag
+	space := ""
+	for _, word := range words[:len(words)-1] {
+		if pending != nil {
+			if pending.Name == "space" {
+				space = word
+			}
+			pending = nil
+			continue
+		}
+		if word == "--" {
+			continue
+		}
+		if strings.HasPrefix(word, "-") && len(word) > 1 {
+			name := strings.TrimLeft(word, "-")
+			if name, value, ok := strings.Cut(name, "="); ok {
+				if name == "space" {
+					space = value
+				}
+				continue
+			}
+			if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+				pending = f
+			}
+			continue
+		}
+		positional = append(positional, word)
+		if cmd, ok := commands[word]; ok && len(positional) == 1 && cmd.flags != nil {
+			fs = cmd.flags()
+		}
+	}
+
+	// Projects come from the space named on the command line.
+	if space != "" {
+		if spaceRoot, err := spaceRoot(cfg, space); err == nil {
+			root = spaceRoot
+		}
+	}
+
+	if pending != nil {
+		return flagValueCandidates(cfg, pending.Name, "", current)
+	}
+	if strings.HasPrefix(current, "-") {
+		if name, value, ok := strings.Cut(strings.TrimLeft(current, "-"), "="); ok {
+			prefix := current[:len(current)-len(value)]
+			if fs.Lookup(name) == nil {
+				return []string{completeWords}
+			}
+			return flagValueCandidates(cfg, name, prefix, value)
+		}
+		return append([]string{completeWords}, flagCandidates(fs, current)...)
+	}
+
+	if len(positional) == 0 {
+		// A command, an existing project to enter, or else a new name, a
+		// URL, or a path to copy.
+		return append(append([]string{completeDirs}, commandCandidates(current)...), projectCandidates(root, current)...)
+	}
+	switch commands[positional[0]].argKind(len(positional) - 1) {
+	case argProject:
+		return append([]string{completeWords}, projectCandidates(root, current)...)
+	case argDir:
+		return []string{completeDirs}
+	default:
+		return []string{completeWords}
+	}
+}
+
+func commandCandidates(current string) []string {
+	var candidates []string
+	for name := range commands {
+		if strings.HasPrefix(name, current) {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func flagCandidates(fs *flag.FlagSet, current string) []string {
+	var candidates []string
+	if strings.HasPrefix("--help", current) {
+		candidates = append(candidates, "--help")
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		if hiddenFlags[f.Name] {
+			return
+		}
+		name := "--" + f.Name
+		if len(f.Name) == 1 {
+			name = "-" + f.Name
+		}
+		if strings.HasPrefix(name, current) {
+			candidates = append(candidates, name)
+		}
+	})
+	sort.Strings(candidates)
+	return candidates
+}
+
+// flagValueCandidates completes the value of the named flag. prefix is put
+// back in front of each candidate for the --flag=value form.
+func flagValueCandidates(cfg config, name, prefix, current string) []string {
+	var values []string
+	switch name {
+	case "init":
+		values = hookShells
+	case "completions":
+		values = []string{"zsh", "bash", "fish"}
+	case "copy-strategy":
+		for _, strategy := range copyStrategies {
+			values = append(values, string(strategy))
+		}
+	case "on-exists":
+		for _, policy := range existsPolicies {
+			values = append(values, string(policy))
+		}
+	case "sort":
+		for _, mode := range sortModes {
+			values = append(values, string(mode))
+		}
+	case "space":
+		values = spaceNames(cfg)
+	case "ignore-from":
+		if prefix == "" {
+			return []string{completeFiles}
+		}
+	}
+	candidates := []string{completeWords}
+	for _, value := range values {
+		if strings.HasPrefix(value, current) {
+			candidates = append(candidates, prefix+value)
+		}
+	}
+	return candidates
+}
+
+// projectCandidates lists project directory names starting with current,
+// newest first. A word that looks like a path gets none, leaving it to the
+// shell's own path completion.
+func projectCandidates(root, current string) []string {
+	if strings.ContainsAny(current, `/\`) || strings.HasPrefix(current, ".") || strings.HasPrefix(current, "~") {
+		return nil
+	}
+	projects, err := listProjects(root)
+	if err != nil {
+		return nil
+	}
+	var candidates []string
+	for _, project := range projects {
+		if strings.HasPrefix(project.Name, current) {
+			candidates = append(candidates, project.Name)
+		}
+	}
+	return candidates
+}
+
+func zshCompletion() string {
+	return `_hatch() {
+  local -a _hatch_lines _hatch_words
+  _hatch_lines=("${(@f)$(command hatch --complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+  _hatch_words=("${(@)_hatch_lines[2,-1]}")
+  case "${_hatch_lines[1]}" in
+    :files) _files ;;
+    :dirs) _files -/ ;;
+  esac
+  (( ${#_hatch_words} )) && compadd -Q -a _hatch_words
+  return 0
+}
+if (( $+functions[compdef] )); then
+  compdef _hatch hatch
+fi`
+}
+
+func bashCompletion() string {
+	return `_hatch() {
+  local _hatch_cur="${COMP_WORDS[COMP_CWORD]}"
+  local IFS=$'\n'
+  local -a _hatch_lines
+  _hatch_lines=($(command hatch --complete -- "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+  COMPREPLY=()
+  case "${_hatch_lines[0]}" in
+    :files) COMPREPLY=($(compgen -f -- "$_hatch_cur")) ;;
+    :dirs) COMPREPLY=($(compgen -d -- "$_hatch_cur")) ;;
+  esac
+  COMPREPLY+=("${_hatch_lines[@]:1}")
+}
+complete -o filenames -F _hatch hatch`
+}
+
+func fishCompletion() string {
+	return `function __hatch_complete
+  set -l _hatch_words (commandline -opc)
+  set -l _hatch_current (commandline -ct)
+  set -e _hatch_words[1]
+  set -l _hatch_lines (command hatch --complete -- $_hatch_words "$_hatch_current" 2>/dev/null)
+  switch "$_hatch_lines[1]"
+    case :files
+      __fish_complete_path "$_hatch_current"
+    case :dirs
+      __fish_complete_directories "$_hatch_current"
+  end
+  if set -q _hatch_lines[2]
+    printf '%s\n' $_hatch_lines[2..-1]
+  end
+end
+complete -c hatch -f -a '(__hatch_complete)'`
+}
@@ -0,0 +1,158 @@
+package hatch
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCompleteArgs(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"2026-02-27-api", "2026-02-28-web", "archive", ".hatch"} {
+		if err := os.MkdirAll(filepath.Join(root, name), 0o755); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"2"}, []string{":dirs", "2026-02-28-web", "2026-02-27-api"}},
+		{[]string{"al"}, []string{":dirs", "allow"}},
+		{[]string{"allow", ""}, []string{":words", "2026-02-28-web", "2026-02-27-api"}},
+		{[]string{"allow", "2026-02-28-web", ""}, []string{":words"}},
+		{[]string{"2026-02-27"}, []string{":dirs", "2026-02-27-api"}},
+		{[]string{"./src"}, []string{":dirs"}},
+		{[]string{"./src", ""}, []string{":words"}},
+		{[]string{"--on"}, []string{":words", "--on-exists"}},
+		{[]string{"--c"}, []string{":words", "--completions", "--copy", "--copy-strategy"}},
+		{[]string{"--on-exists", ""}, []string{":words", "fail", "suffix", "open"}},
+		{[]string{"--on-exists=s"}, []string{":words", "--on-exists=suffix"}},
+		{[]string{"--copy-strategy", "re"}, []string{":words", "reflink"}},
+		{[]string{"--init", "f"}, []string{":words", "fish"}},
+		{[]string{"--ignore-from", ""}, []string{":files"}},
+		{[]string{"du", "--s"}, []string{":words", "--sort"}},
+		{[]string{"du", "--sort", "si"}, []string{":words", "size"}},
+		{[]string{"du", "--json", "2026-02-28-web", "2026-02-2"}, []string{":words", "2026-02-28-web", "2026-02-27-api"}},
+		{[]string{"adopt", ""}, []string{":dirs"}},
+		{[]string{"adopt", "--l"}, []string{":words", "--link"}},
+		{[]string{"adopt", "./src", ""}, []string{":words"}},
+		{[]string{"promote", "2026-02-28-web", ""}, []string{":dirs"}},
+		{[]string{"--edit", "--exclude", "*.log", "2026"}, []string{":dirs", "2026-02-28-web", "2026-02-27-api"}},
+	}
+	for _, tt := range tests {
+		if got := completeArgs(root, config{}, tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("completeArgs(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteArgsHidesInternalFlags(t *testing.T) {
+	got := strings.Join(completeArgs(t.TempDir(), config{}, []string{"-"}), "\n") + "\n"
+	for _, hidden := range []string{"--cwd-file", "--complete\n"} {
+		if strings.Contains(got, hidden) {
+			t.Fatalf("completion offered %s:\n%s", hidden, got)
+		}
+	}
+	if !strings.Contains(got, "-c\n") || !strings.Contains(got, "--help\n") {
+		t.Fatalf("completion missing flags:\n%s", got)
+	}
+}
+
+func TestRunExistingProjectNameHonorsOnExists(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	t.Setenv("HATCH_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	project := filepath.Join(root, "2026-01-15-api")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	hatch := func(args ...string) (string, error) {
+		t.Helper()
+		cwdFile := filepath.Join(t.TempDir(), "cwd")
+		err := run(append([]string{"--cwd-file", cwdFile}, args...), strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow)
+		cwd, _ := os.ReadFile(cwdFile)
+		return string(cwd), err
+	}
+
+	var exists *projectExistsError
+	if _, err := hatch("2026-01-15-api"); !errors.As(err, &exists) || exists.Path != project {
+		t.Fatalf("expected the default fail policy to refuse, got %v", err)
+	}
+	if cwd, err := hatch("--on-exists", "open", "2026-01-15-api"); err != nil || cwd != project {
+		t.Fatalf("cwd = %q, %v; want %q", cwd, err, project)
+	}
+	if cwd, err := hatch("--on-exists", "suffix", "2026-01-15-api"); err != nil || cwd != project+"-2" {
+		t.Fatalf("cwd = %q, %v; want %q", cwd, err, project+"-2")
+	}
+	if _, err := os.Stat(filepath.Join(root, "2026-02-28-2026-01-15-api")); !os.IsNotExist(err) {
+		t.Fatalf("expected no nested project, stat err = %v", err)
+	}
+}
+
+func TestRunCompletionsAndInit(t *testing.T) {
+	for _, shell := range []string{"zsh", "bash", "fish"} {
+		out := new(bytes.Buffer)
+		if err := run([]string{"--completions", shell}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("--completions %s returned error: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "command hatch --complete --") {
+			t.Fatalf("%s completion does not call back into hatch:\n%s", shell, out.String())
+		}
+
+		out.Reset()
+		if err := run([]string{"--init", shell}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("--init %s returned error: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "--cwd-file") || !strings.Contains(out.String(), "--complete --") {
+			t.Fatalf("%s init missing hook or completions:\n%s", shell, out.String())
+		}
+	}
+	if err := run([]string{"--completions", "tcsh"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil {
+		t.Fatal("expected error for unsupported shell")
+	}
+}
+
+func TestBashCompletionCallsHatch(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("bash not available")
+	}
+	root := filepath.Join(t.TempDir(), "hatchery")
+	if err := os.MkdirAll(filepath.Join(root, "2026-02-28-api"), 0o755); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	binDir := t.TempDir()
+	wrapper := "#!/bin/sh\nHATCH_TEST_MAIN=1 exec " + os.Args[0] + " \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "hatch"), []byte(wrapper), 0o755); err != nil {
+		t.Fatalf("write hatch wrapper: %v", err)
+	}
+
+	script := bashCompletion() + `
+complete_words() {
+  COMP_WORDS=("$@")
+  COMP_CWORD=$(($# - 1))
+  _hatch
+  printf '%s\n' "${COMPREPLY[@]}"
+}
+complete_words hatch 2026
+complete_words hatch --on-exists su
+`
+	cmd := exec.Command("bash", "--norc", "--noprofile", "-c", script)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), "PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"), "HATCHERY_HOME="+root)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash completion failed: %v\n%s", err, output)
+	}
+	if got, want := strings.TrimSpace(string(output)), "2026-02-28-api\nsuffix"; got != want {
+		t.Fatalf("bash completions = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package hatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config is read from ~/.config/hatch/config.json. Every field is optional;
+// command-line flags take precedence over the values here.
+type config struct {
+	// OnExists is fail, suffix, or open; see existsPolicy.
+	OnExists string        `json:"on_exists"`
+	Copy     copyConfig    `json:"copy"`
+	Editor   editorConfig  `json:"editor"`
+	Session  sessionConfig `json:"session"`
+	Env      envConfig     `json:"env"`
+	Browser  browserConfig `json:"browser"`
+	Promote  promoteConfig `json:"promote"`
+	// Spaces names extra hatchery roots, such as {"work": "~/work/hatchery"},
+	// picked with --space. Space names the one used without --space.
+	Spaces map[string]string `json:"spaces"`
+	Space  string            `json:"space"`
+}
+
+type browserConfig struct {
+	// Sizes adds a column with each project's disk usage, scanned in the
+	// background.
+	Sizes bool `json:"sizes"`
+}
+
+type copyConfig struct {
+	// IgnoreFrom names per-directory ignore files, such as ".gitignore".
+	IgnoreFrom []string `json:"ignore_from"`
+	// Exclude holds extra gitignore-style patterns relative to the source.
+	Exclude []string `json:"exclude"`
+	// Strategy is one of auto, reflink, copy-range, plain, or hardlink.
+	Strategy string `json:"strategy"`
+	// Workers bounds concurrent file copies; zero picks a default.
+	Workers int `json:"workers"`
+	// Preserve keeps times, xattrs, hardlinks, and special files.
+	Preserve bool `json:"preserve"`
+}
+
+func configPath() (string, error) {
+	if env := strings.TrimSpace(os.Getenv("HATCH_CONFIG")); env != "" {
+		return expandPath(env)
+	}
+	if xdg := strings.TrimSpace(os.Getenv("XDG_CONFIG_HOME")); xdg != "" {
+		return filepath.Join(xdg, "hatch", "config.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "hatch", "config.json"), nil
+}
+
+func loadConfig() (config, error) {
+	var cfg config
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c config) copyOptions() (copyOptions, error) {
+	strategy, err := parseCopyStrategy(c.Copy.Strategy)
+	if err != nil {
+		return copyOptions{}, fmt.Errorf("config copy.strategy: %w", err)
+	}
+	return copyOptions{
+		ignore: ignoreRules{
+			files:   c.Copy.IgnoreFrom,
+			exclude: c.Copy.Exclude,
+		},
+		strategy: strategy,
+		workers:  c.Copy.Workers,
+		preserve: c.Copy.Preserve,
+	}, nil
+}
+
+func (c config) existsPolicy() (existsPolicy, error) {
+	policy, err := parseExistsPolicy(c.OnExists)
+	if err != nil {
+		return "", fmt.Errorf("config on_exists: %w", err)
+	}
+	return policy, nil
+}
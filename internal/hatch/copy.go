@@ -0,0 +1,582 @@
+package hatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type copyStrategy string
+
+const (
+	// strategyAuto tries reflink, then copy-range, then plain for each file.
+	strategyAuto      copyStrategy = "auto"
+	strategyReflink   copyStrategy = "reflink"
+	strategyCopyRange copyStrategy = "copy-range"
+	strategyPlain     copyStrategy = "plain"
+	// strategyHardlink links files instead of copying them. A link shares
+	// its content and permissions with the source, so both keep the
+	// source's mode and an edit to either shows up in the other.
+	strategyHardlink copyStrategy = "hardlink"
+)
+
+var copyStrategies = []copyStrategy{strategyAuto, strategyReflink, strategyCopyRange, strategyPlain, strategyHardlink}
+
+func parseCopyStrategy(value string) (copyStrategy, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return strategyAuto, nil
+	}
+	for _, strategy := range copyStrategies {
+		if string(strategy) == value {
+			return strategy, nil
+		}
+	}
+	names := make([]string, 0, len(copyStrategies))
+	for _, strategy := range copyStrategies {
+		names = append(names, string(strategy))
+	}
+	return "", fmt.Errorf("unknown copy strategy %q (use %s)", value, strings.Join(names, ", "))
+}
+
+type copyOptions struct {
+	// progress, when set, receives a snapshot after every copied entry.
+	progress func(copyProgress)
+	ignore   ignoreRules
+	strategy copyStrategy
+	// workers bounds concurrent file copies; zero picks a default from the
+	// CPU count.
+	workers int
+	// preserve keeps access and modification times, extended attributes,
+	// exact permission bits, hardlinks between copied files, and named
+	// pipes and device nodes.
+	preserve bool
+}
+
+// copyResult describes how a copy was carried out.
+type copyResult struct {
+	// Strategies counts regular files by the method that copied them.
+	Strategies map[copyStrategy]int
+	// Skipped lists entries, or parts of entries such as extended
+	// attributes, that could not be reproduced in the target.
+	Skipped []copySkip
+}
+
+// copySkip is an entry the copy left out. Path is relative to the source.
+type copySkip struct {
+	Path   string
+	Reason string
+}
+
+func (s copySkip) String() string {
+	return filepath.ToSlash(s.Path) + ": " + s.Reason
+}
+
+// summary names the strategies used, most common first, e.g.
+// "reflink" or "reflink 120, plain 3", followed by a count of skipped
+// entries.
+func (r copyResult) summary() string {
+	strategies := make([]copyStrategy, 0, len(r.Strategies))
+	for strategy := range r.Strategies {
+		strategies = append(strategies, strategy)
+	}
+	sort.Slice(strategies, func(i, j int) bool {
+		if r.Strategies[strategies[i]] == r.Strategies[strategies[j]] {
+			return strategies[i] < strategies[j]
+		}
+		return r.Strategies[strategies[i]] > r.Strategies[strategies[j]]
+	})
+	parts := make([]string, 0, len(strategies)+1)
+	if len(strategies) == 1 {
+		parts = append(parts, string(strategies[0]))
+	} else {
+		for _, strategy := range strategies {
+			parts = append(parts, fmt.Sprintf("%s %d", strategy, r.Strategies[strategy]))
+		}
+	}
+	if len(r.Skipped) > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", len(r.Skipped)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sharedWarning explains that hardlinked files are shared with the source,
+// or returns "" when nothing was hardlinked.
+func (r copyResult) sharedWarning() string {
+	if r.Strategies[strategyHardlink] == 0 {
+		return ""
+	}
+	return "warning: hardlinked files share their content with the source; editing either changes both"
+}
+
+type copyProgress struct {
+	Files      int
+	TotalFiles int
+	Bytes      int64
+	TotalBytes int64
+}
+
+// copyDir copies source into target. A single walker creates directories
+// and symlinks in walk order, so every directory exists before its files are
+// queued, while a bounded pool of workers copies regular files concurrently.
+// Per-entry failures are collected rather than stopping the copy; a
+// cancelled ctx stops both the walker and the workers. Named pipes, devices,
+// and sockets are never opened; they are recreated in preserve mode where
+// possible and listed in the result's Skipped otherwise.
+func copyDir(ctx context.Context, source, target string, opts copyOptions) (copyResult, error) {
+	result := copyResult{Strategies: map[copyStrategy]int{}}
+	var progress copyProgress
+	if opts.progress != nil {
+		totals, err := scanCopyTotals(ctx, source, opts)
+		if err != nil {
+			return result, err
+		}
+		progress = totals
+		opts.progress(progress)
+	}
+
+	var (
+		mu     sync.Mutex
+		failed copyErrors
+	)
+	// finish records a copied entry; strategy is empty for anything that
+	// is not a copied regular file.
+	finish := func(size int64, strategy copyStrategy) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strategy != "" {
+			result.Strategies[strategy]++
+		}
+		progress.Files++
+		progress.Bytes += size
+		if opts.progress != nil {
+			opts.progress(progress)
+		}
+	}
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed.add(err)
+	}
+	skip := func(rel string, reasons ...string) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, reason := range reasons {
+			result.Skipped = append(result.Skipped, copySkip{Path: rel, Reason: reason})
+		}
+	}
+
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return result, fmt.Errorf("create directory %s: %w", target, err)
+	}
+	// In preserve mode directories are created writable and get their own
+	// mode, times, and xattrs only after everything inside them exists.
+	var dirs []copyJob
+	if opts.preserve {
+		info, err := os.Stat(source)
+		if err != nil {
+			return result, fmt.Errorf("read source directory: %w", err)
+		}
+		meta, err := readFileMeta(source, info)
+		if err != nil {
+			return result, err
+		}
+		dirs = append(dirs, copyJob{source: source, target: target, rel: ".", info: info, meta: meta})
+	}
+	// Files sharing an inode in the source are linked to the first copy
+	// once all workers are done.
+	linked := map[fileID]string{}
+	var links []copyJob
+
+	jobs := make(chan copyJob, opts.copyWorkers()*4)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.copyWorkers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				mode := job.info.Mode().Perm()
+				if opts.preserve {
+					mode |= 0o200
+				}
+				used, err := copyFile(job.source, job.target, mode, opts.strategy)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				if opts.preserve && used != strategyHardlink {
+					rejected, err := preserveMetadata(job)
+					skip(job.rel, rejected...)
+					if err != nil {
+						fail(err)
+						continue
+					}
+				}
+				finish(job.info.Size(), used)
+			}
+		}()
+	}
+
+	walkErr := walkCopySource(ctx, source, opts, func(path, rel string, d fs.DirEntry) error {
+		dstPath := filepath.Join(target, rel)
+		info, err := d.Info()
+		if err != nil {
+			fail(fmt.Errorf("read entry metadata for %s: %w", path, err))
+			return nil
+		}
+		job := copyJob{source: path, target: dstPath, rel: rel, info: info}
+		if opts.preserve {
+			if job.meta, err = readFileMeta(path, info); err != nil {
+				fail(err)
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			perm := mode.Perm()
+			if opts.preserve {
+				perm |= 0o700
+			}
+			if err := os.MkdirAll(dstPath, perm); err != nil {
+				fail(fmt.Errorf("create directory %s: %w", dstPath, err))
+				return filepath.SkipDir
+			}
+			if opts.preserve {
+				dirs = append(dirs, job)
+			}
+			return nil
+		case mode&os.ModeSymlink != 0:
+			if err := copySymlink(path, dstPath); err != nil {
+				fail(err)
+				return nil
+			}
+			if opts.preserve {
+				if err := setLinkTimes(dstPath, job.meta.atime, info.ModTime()); err != nil {
+					fail(err)
+					return nil
+				}
+			}
+			finish(0, "")
+			return nil
+		case !mode.IsRegular():
+			if reason := copySpecialFile(job, opts.preserve); reason != "" {
+				skip(rel, reason)
+			}
+			return nil
+		}
+
+		if opts.preserve && opts.strategy != strategyHardlink && job.meta.nlink > 1 {
+			if first, ok := linked[job.meta.id]; ok {
+				job.source = first
+				links = append(links, job)
+				return nil
+			}
+			linked[job.meta.id] = dstPath
+		}
+
+		select {
+		case jobs <- job:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	for _, link := range links {
+		if err := os.Link(link.source, link.target); err != nil {
+			fail(fmt.Errorf("hardlink %s -> %s: %w", link.source, link.target, err))
+			continue
+		}
+		finish(link.info.Size(), "")
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		rejected, err := preserveMetadata(dirs[i])
+		skip(dirs[i].rel, rejected...)
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	return result, errors.Join(walkErr, failed.err())
+}
+
+type copyJob struct {
+	source string
+	target string
+	// rel is the entry's path relative to the copy source.
+	rel  string
+	info fs.FileInfo
+	// meta is only read in preserve mode.
+	meta fileMeta
+}
+
+// fileMeta carries what preserve mode needs beyond fs.FileInfo. Platforms
+// without inode numbers leave id zero.
+type fileMeta struct {
+	atime time.Time
+	id    fileID
+	nlink uint64
+	rdev  uint64
+}
+
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+var errSpecialFileUnsupported = errors.New("not supported on this platform")
+
+// preserveMode keeps the setuid, setgid, and sticky bits, which a plain
+// permission copy drops.
+const preserveMode = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// preserveMetadata copies xattrs, then the exact mode, then times from the
+// job's source entry onto its target. Times come last because the other
+// two would otherwise bump them. It returns xattrs the target rejected.
+func preserveMetadata(job copyJob) ([]string, error) {
+	rejected, err := copyXattrs(job.source, job.target)
+	if err != nil {
+		return nil, err
+	}
+	reasons := make([]string, 0, len(rejected))
+	for _, name := range rejected {
+		reasons = append(reasons, "extended attribute "+name)
+	}
+	if err := os.Chmod(job.target, job.info.Mode()&preserveMode); err != nil {
+		return reasons, fmt.Errorf("set mode on %s: %w", job.target, err)
+	}
+	if err := os.Chtimes(job.target, job.meta.atime, job.info.ModTime()); err != nil {
+		return reasons, fmt.Errorf("set times on %s: %w", job.target, err)
+	}
+	return reasons, nil
+}
+
+// copySpecialFile recreates named pipes and devices in preserve mode. It
+// returns why the entry was skipped, or "" when it was recreated.
+func copySpecialFile(job copyJob, preserve bool) string {
+	mode := job.info.Mode()
+	var kind string
+	switch {
+	case mode&fs.ModeNamedPipe != 0:
+		kind = "named pipe"
+	case mode&fs.ModeCharDevice != 0:
+		kind = "character device"
+	case mode&fs.ModeDevice != 0:
+		kind = "block device"
+	case mode&fs.ModeSocket != 0:
+		return "socket cannot be copied"
+	default:
+		return "irregular file cannot be copied"
+	}
+	if !preserve {
+		return kind + " is only recreated with --preserve"
+	}
+
+	if err := os.MkdirAll(filepath.Dir(job.target), 0o755); err != nil {
+		return fmt.Sprintf("%s: %v", kind, err)
+	}
+	if err := makeSpecialFile(job.target, mode, job.meta.rdev); err != nil {
+		return fmt.Sprintf("%s: %v", kind, err)
+	}
+	if _, err := preserveMetadata(job); err != nil {
+		return fmt.Sprintf("%s: %v", kind, err)
+	}
+	return ""
+}
+
+// maxReportedCopyErrors bounds how many failures are kept in full, so a full
+// disk does not produce one error per remaining file.
+const maxReportedCopyErrors = 10
+
+type copyErrors struct {
+	errs  []error
+	total int
+}
+
+func (e *copyErrors) add(err error) {
+	e.total++
+	if len(e.errs) < maxReportedCopyErrors {
+		e.errs = append(e.errs, err)
+	}
+}
+
+func (e *copyErrors) err() error {
+	if e.total == 0 {
+		return nil
+	}
+	joined := errors.Join(e.errs...)
+	if extra := e.total - len(e.errs); extra > 0 {
+		return fmt.Errorf("%w\n(and %d more errors)", joined, extra)
+	}
+	return joined
+}
+
+func (o copyOptions) copyWorkers() int {
+	if o.workers > 0 {
+		return o.workers
+	}
+	return max(4, runtime.NumCPU()*2)
+}
+
+func copySymlink(source, target string) error {
+	linkTarget, err := os.Readlink(source)
+	if err != nil {
+		return fmt.Errorf("read symlink %s: %w", source, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create parent directory for symlink %s: %w", target, err)
+	}
+	if err := os.Symlink(linkTarget, target); err != nil {
+		return fmt.Errorf("create symlink %s: %w", target, err)
+	}
+	return nil
+}
+
+// walkCopySource visits every entry below source that survives the ignore
+// rules, parents before children. rel is relative to source.
+func walkCopySource(ctx context.Context, source string, opts copyOptions, visit func(path, rel string, d fs.DirEntry) error) error {
+	matcher := newIgnoreMatcher(ctx, source, opts.ignore)
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return fmt.Errorf("resolve relative path: %w", err)
+		}
+		if rel == "." {
+			return matcher.enterDir("")
+		}
+
+		slashRel := filepath.ToSlash(rel)
+		skip, err := matcher.skip(slashRel, d.IsDir())
+		if err != nil {
+			return err
+		}
+		if skip {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if err := matcher.enterDir(slashRel); err != nil {
+				return err
+			}
+		}
+		return visit(path, rel, d)
+	})
+}
+
+// scanCopyTotals counts the files and bytes copyDir will copy so progress can
+// be reported against a total.
+func scanCopyTotals(ctx context.Context, source string, opts copyOptions) (copyProgress, error) {
+	var totals copyProgress
+	err := walkCopySource(ctx, source, opts, func(path, _ string, d fs.DirEntry) error {
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("read entry metadata for %s: %w", path, err)
+		}
+		switch {
+		case info.Mode().IsRegular():
+			totals.TotalFiles++
+			totals.TotalBytes += info.Size()
+		case info.Mode()&os.ModeSymlink != 0:
+			totals.TotalFiles++
+		}
+		return nil
+	})
+	if err != nil {
+		return copyProgress{}, fmt.Errorf("scan source directory: %w", err)
+	}
+	return totals, nil
+}
+
+// copyFile copies one regular file using strategy and returns the method
+// that was actually used. Only strategyAuto falls back to slower methods;
+// an explicit strategy fails when the filesystem does not support it.
+func copyFile(source, target string, mode fs.FileMode, strategy copyStrategy) (copyStrategy, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", fmt.Errorf("create parent directory for file %s: %w", target, err)
+	}
+
+	if strategy == strategyHardlink {
+		if err := os.Link(source, target); err != nil {
+			return "", fmt.Errorf("hardlink %s -> %s: %w", source, target, err)
+		}
+		return strategyHardlink, nil
+	}
+
+	src, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("open source file %s: %w", source, err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return "", fmt.Errorf("open destination file %s: %w", target, err)
+	}
+	defer dst.Close()
+
+	switch strategy {
+	case strategyReflink:
+		if err := reflinkFile(dst, src); err != nil {
+			return "", fmt.Errorf("reflink %s -> %s: %w", source, target, err)
+		}
+		return strategyReflink, nil
+	case strategyCopyRange:
+		if _, err := copyFileRange(dst, src); err != nil {
+			return "", fmt.Errorf("copy-range %s -> %s: %w", source, target, err)
+		}
+		return strategyCopyRange, nil
+	case strategyPlain:
+	default:
+		if err := reflinkFile(dst, src); err == nil {
+			return strategyReflink, nil
+		}
+		written, err := copyFileRange(dst, src)
+		if err == nil {
+			return strategyCopyRange, nil
+		}
+		if written > 0 {
+			return "", fmt.Errorf("copy file %s -> %s: %w", source, target, err)
+		}
+	}
+
+	// Hide the *os.File types so io.Copy cannot pick copy_file_range or
+	// sendfile behind our back; plain means a userspace copy.
+	if _, err := io.Copy(struct{ io.Writer }{dst}, struct{ io.Reader }{src}); err != nil {
+		return "", fmt.Errorf("copy file %s -> %s: %w", source, target, err)
+	}
+	return strategyPlain, nil
+}
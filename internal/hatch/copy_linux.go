@@ -0,0 +1,32 @@
+//go:build linux
+
+package hatch
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflinkFile shares src's extents with dst via the FICLONE ioctl. It only
+// succeeds on copy-on-write filesystems such as Btrfs and XFS.
+func reflinkFile(dst, src *os.File) error {
+	return unix.IoctlFileClone(int(dst.Fd()), int(src.Fd()))
+}
+
+// copyFileRange copies src into dst inside the kernel. It returns how many
+// bytes were written so callers can tell "unsupported" (nothing written)
+// apart from a failure midway through.
+func copyFileRange(dst, src *os.File) (int64, error) {
+	var written int64
+	for {
+		n, err := unix.CopyFileRange(int(src.Fd()), nil, int(dst.Fd()), nil, 1<<30, 0)
+		if err != nil {
+			return written, err
+		}
+		if n == 0 {
+			return written, nil
+		}
+		written += int64(n)
+	}
+}
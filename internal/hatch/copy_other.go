@@ -0,0 +1,18 @@
+//go:build !linux
+
+package hatch
+
+import (
+	"errors"
+	"os"
+)
+
+var errCopyStrategyUnsupported = errors.New("not supported on this platform")
+
+func reflinkFile(dst, src *os.File) error {
+	return errCopyStrategyUnsupported
+}
+
+func copyFileRange(dst, src *os.File) (int64, error) {
+	return 0, errCopyStrategyUnsupported
+}
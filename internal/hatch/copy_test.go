@@ -0,0 +1,214 @@
+package hatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseCopyStrategy(t *testing.T) {
+	t.Parallel()
+
+	if got, err := parseCopyStrategy(""); err != nil || got != strategyAuto {
+		t.Fatalf("empty strategy = %q, %v; want auto", got, err)
+	}
+	if got, err := parseCopyStrategy(" Reflink "); err != nil || got != strategyReflink {
+		t.Fatalf("reflink strategy = %q, %v", got, err)
+	}
+	if _, err := parseCopyStrategy("rsync"); err == nil {
+		t.Fatalf("expected unknown strategy to fail")
+	}
+}
+
+func TestCopyFileStrategies(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source.txt")
+	if err := os.WriteFile(source, []byte("payload"), 0o640); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	tests := []struct {
+		strategy copyStrategy
+		allowed  []copyStrategy
+	}{
+		{strategy: strategyAuto, allowed: []copyStrategy{strategyReflink, strategyCopyRange, strategyPlain}},
+		{strategy: strategyPlain, allowed: []copyStrategy{strategyPlain}},
+		{strategy: strategyHardlink, allowed: []copyStrategy{strategyHardlink}},
+	}
+	for _, tc := range tests {
+		target := filepath.Join(t.TempDir(), "nested", "target.txt")
+		used, err := copyFile(source, target, 0o640, tc.strategy)
+		if err != nil {
+			t.Fatalf("copyFile(%s) returned error: %v", tc.strategy, err)
+		}
+		allowed := false
+		for _, strategy := range tc.allowed {
+			allowed = allowed || used == strategy
+		}
+		if !allowed {
+			t.Fatalf("copyFile(%s) used %q, want one of %v", tc.strategy, used, tc.allowed)
+		}
+		content, err := os.ReadFile(target)
+		if err != nil || string(content) != "payload" {
+			t.Fatalf("copyFile(%s) content = %q, %v", tc.strategy, content, err)
+		}
+	}
+}
+
+func TestCopyFileHardlinkSharesInode(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source.txt")
+	if err := os.WriteFile(source, []byte("payload"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	target := filepath.Join(filepath.Dir(source), "target.txt")
+	if _, err := copyFile(source, target, 0o644, strategyHardlink); err != nil {
+		t.Fatalf("copyFile returned error: %v", err)
+	}
+
+	srcInfo, err := os.Stat(source)
+	if err != nil {
+		t.Fatalf("stat source: %v", err)
+	}
+	dstInfo, err := os.Stat(target)
+	if err != nil {
+		t.Fatalf("stat target: %v", err)
+	}
+	if !os.SameFile(srcInfo, dstInfo) {
+		t.Fatalf("expected hardlinked files to share an inode")
+	}
+	if runtime.GOOS != "windows" && srcInfo.Mode().Perm() != 0o644 {
+		t.Fatalf("expected the source mode to be left alone, got %v", srcInfo.Mode().Perm())
+	}
+}
+
+func TestCopyResultSummary(t *testing.T) {
+	t.Parallel()
+
+	if got := (copyResult{}).summary(); got != "" {
+		t.Fatalf("empty summary = %q", got)
+	}
+	single := copyResult{Strategies: map[copyStrategy]int{strategyReflink: 4}}
+	if got := single.summary(); got != "reflink" {
+		t.Fatalf("single summary = %q", got)
+	}
+	mixed := copyResult{Strategies: map[copyStrategy]int{strategyPlain: 3, strategyReflink: 120}}
+	if got := mixed.summary(); got != "reflink 120, plain 3" {
+		t.Fatalf("mixed summary = %q", got)
+	}
+	skipped := copyResult{Strategies: map[copyStrategy]int{strategyPlain: 2}, Skipped: []copySkip{{Path: "fifo", Reason: "named pipe"}}}
+	if got := skipped.summary(); got != "plain, 1 skipped" {
+		t.Fatalf("skipped summary = %q", got)
+	}
+}
+
+func TestCopyDirCopiesInParallel(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	files := map[string]string{}
+	for i := 0; i < 200; i++ {
+		files[fmt.Sprintf("dir-%02d/file-%03d.txt", i%10, i)] = fmt.Sprintf("content %d", i)
+	}
+	files["link-target.txt"] = "target"
+	writeTree(t, source, files)
+	if err := os.Symlink("link-target.txt", filepath.Join(source, "link")); err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+
+	target := filepath.Join(t.TempDir(), "target")
+	result, err := copyDir(context.Background(), source, target, copyOptions{workers: 8})
+	if err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+
+	copied := 0
+	for _, count := range result.Strategies {
+		copied += count
+	}
+	if copied != len(files) {
+		t.Fatalf("copied %d files, want %d", copied, len(files))
+	}
+	for name, want := range files {
+		content, err := os.ReadFile(filepath.Join(target, filepath.FromSlash(name)))
+		if err != nil || string(content) != want {
+			t.Fatalf("copied %s = %q, %v; want %q", name, content, err, want)
+		}
+	}
+	if link, err := os.Readlink(filepath.Join(target, "link")); err != nil || link != "link-target.txt" {
+		t.Fatalf("symlink = %q, %v", link, err)
+	}
+}
+
+func TestCopyDirAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+
+	// Directories squatting on the destination paths make those two copies
+	// fail without affecting the third.
+	target := filepath.Join(t.TempDir(), "target")
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.MkdirAll(filepath.Join(target, name), 0o755); err != nil {
+			t.Fatalf("create blocking directory: %v", err)
+		}
+	}
+
+	_, err := copyDir(context.Background(), source, target, copyOptions{workers: 2})
+	if err == nil {
+		t.Fatalf("expected copy errors")
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Fatalf("expected error to mention %s, got %v", name, err)
+		}
+	}
+	if content, err := os.ReadFile(filepath.Join(target, "c.txt")); err != nil || string(content) != "c" {
+		t.Fatalf("expected c.txt to be copied despite other failures: %q, %v", content, err)
+	}
+}
+
+// BenchmarkCopyDir100kSmallFiles mirrors a JavaScript project with a large
+// node_modules tree. Run with: go test -bench CopyDir -benchtime 3x
+func BenchmarkCopyDir100kSmallFiles(b *testing.B) {
+	source := filepath.Join(b.TempDir(), "source")
+	payload := make([]byte, 512)
+	for dir := 0; dir < 1000; dir++ {
+		dirPath := filepath.Join(source, "node_modules", fmt.Sprintf("pkg-%04d", dir))
+		if err := os.MkdirAll(dirPath, 0o755); err != nil {
+			b.Fatalf("create fixture dir: %v", err)
+		}
+		for file := 0; file < 100; file++ {
+			if err := os.WriteFile(filepath.Join(dirPath, fmt.Sprintf("f%03d.js", file)), payload, 0o644); err != nil {
+				b.Fatalf("write fixture file: %v", err)
+			}
+		}
+	}
+
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				target := filepath.Join(b.TempDir(), "target")
+				if _, err := copyDir(context.Background(), source, target, copyOptions{workers: workers}); err != nil {
+					b.Fatalf("copyDir returned error: %v", err)
+				}
+				b.StopTimer()
+				if err := os.RemoveAll(target); err != nil {
+					b.Fatalf("remove target: %v", err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package hatch
+
+import "syscall"
+
+// errCrossDevice is what a rename across filesystems fails with.
+var errCrossDevice error = syscall.EXDEV
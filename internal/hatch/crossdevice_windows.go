@@ -0,0 +1,8 @@
+//go:build windows
+
+package hatch
+
+import "golang.org/x/sys/windows"
+
+// errCrossDevice is what a rename across volumes fails with.
+var errCrossDevice error = windows.ERROR_NOT_SAME_DEVICE
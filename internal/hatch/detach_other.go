@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package hatch
+
+import "os/exec"
+
+func detachProcess(*exec.Cmd) {}
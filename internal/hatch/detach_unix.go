@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package hatch
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachProcess starts cmd in its own session so closing the terminal does
+// not take it down.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
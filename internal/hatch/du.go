@@ -0,0 +1,143 @@
+package hatch
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// duTopDirs is how many of a project's biggest directories hatch du shows.
+const duTopDirs = 3
+
+const duUsage = "usage: hatch du [--sort date|modified|size|name|frecency] [--json] [project...]"
+
+type duOptions struct {
+	sort string
+	json bool
+}
+
+func newDuFlagSet(options *duOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("du", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&options.sort, "sort", string(sortDate), "order by date, modified, size, name, or frecency")
+	fs.BoolVar(&options.json, "json", false, "print JSON")
+	return fs
+}
+
+type duReport struct {
+	Projects []duProject `json:"projects"`
+	Size     int64       `json:"size"`
+	Files    int         `json:"files"`
+}
+
+type duProject struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	Files   int       `json:"files"`
+	Biggest []dirSize `json:"biggest"`
+}
+
+// runDu reports the disk usage of every project, or of the named ones:
+// hatch du [--sort size] [--json] [project...].
+func runDu(c commandContext, args []string) error {
+	var options duOptions
+	fs := newDuFlagSet(&options)
+	args, err := parseCommandFlags(fs, args, duUsage)
+	if err != nil {
+		return err
+	}
+	mode, err := parseSortMode(options.sort)
+	if err != nil {
+		return fmt.Errorf("--sort: %w", err)
+	}
+
+	var projects []Project
+	if len(args) == 0 {
+		if projects, err = listProjects(c.root); err != nil {
+			return err
+		}
+	}
+	for _, ref := range args {
+		project, err := c.resolveProject(ref)
+		if err != nil {
+			return err
+		}
+		projects = append(projects, project)
+	}
+	// Projects given by path may live in other spaces, each with its own
+	// metadata and size cache.
+	paths := make(map[string][]string)
+	metas := make(map[string]metadata)
+	for _, project := range projects {
+		root := filepath.Dir(project.Path)
+		if _, ok := metas[root]; !ok {
+			if metas[root], err = loadMetadata(root); err != nil {
+				return err
+			}
+		}
+		paths[root] = append(paths[root], project.Path)
+	}
+	stats := make(map[string]projectStats, len(projects))
+	for root, rootPaths := range paths {
+		maps.Copy(stats, scanProjects(root, rootPaths, false))
+	}
+	meta := func(project Project) projectMeta {
+		return metas[filepath.Dir(project.Path)].project(project.Path)
+	}
+	now := c.now()
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i], projects[j]
+		return compareProjects(mode, a, b, meta(a), meta(b), stats[a.Path], stats[b.Path], now) < 0
+	})
+
+	report := duReport{Projects: make([]duProject, 0, len(projects))}
+	for _, project := range projects {
+		s := stats[project.Path]
+		biggest := s.Subdirs[:min(len(s.Subdirs), duTopDirs)]
+		if biggest == nil {
+			biggest = []dirSize{}
+		}
+		report.Projects = append(report.Projects, duProject{
+			Name:    project.Name,
+			Path:    project.Path,
+			Size:    s.Size,
+			Files:   s.Files,
+			Biggest: biggest,
+		})
+		report.Size += s.Size
+		report.Files += s.Files
+	}
+
+	if options.json {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode report: %w", err)
+		}
+		fmt.Fprintln(c.out, string(data))
+		return nil
+	}
+	printDuReport(c.out, report)
+	return nil
+}
+
+func printDuReport(out io.Writer, report duReport) {
+	nameWidth := 0
+	for _, project := range report.Projects {
+		nameWidth = max(nameWidth, len(project.Name))
+	}
+	for _, project := range report.Projects {
+		biggest := make([]string, len(project.Biggest))
+		for i, dir := range project.Biggest {
+			biggest[i] = dir.Name + " " + formatBytes(dir.Size)
+		}
+		line := fmt.Sprintf("%10s  %8d files  %-*s  %s", formatBytes(project.Size), project.Files, nameWidth, project.Name, strings.Join(biggest, ", "))
+		fmt.Fprintln(out, strings.TrimRight(line, " "))
+	}
+	fmt.Fprintf(out, "%10s  %8d files  total of %d projects\n", formatBytes(report.Size), report.Files, len(report.Projects))
+}
@@ -0,0 +1,133 @@
+package hatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunDu(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	writeTree(t, filepath.Join(root, "2026-02-28-small"), map[string]string{"main.go": "package main\n"})
+	writeTree(t, filepath.Join(root, "2026-01-10-web"), map[string]string{
+		"node_modules/a/index.js": strings.Repeat("x", 4096),
+		".git/objects/pack":       strings.Repeat("x", 2048),
+		"src/app.js":              strings.Repeat("x", 100),
+		"dist/app.js":             "",
+		"README.md":               "hi",
+	})
+
+	hatch := func(args ...string) string {
+		t.Helper()
+		out := new(bytes.Buffer)
+		if err := run(args, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("hatch %v returned error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	lines := strings.Split(strings.TrimSpace(hatch("du", "--sort", "size")), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected two projects and a total:\n%s", strings.Join(lines, "\n"))
+	}
+	if !strings.Contains(lines[0], "2026-01-10-web") || !strings.HasSuffix(lines[0], "node_modules 4.0 KiB, .git 2.0 KiB, src 100 B") {
+		t.Fatalf("expected the biggest project with its biggest directories first, got %q", lines[0])
+	}
+	if !strings.Contains(lines[0], "5 files") || !strings.Contains(lines[2], "total of 2 projects") {
+		t.Fatalf("unexpected report:\n%s", strings.Join(lines, "\n"))
+	}
+
+	var report duReport
+	if err := json.Unmarshal([]byte(hatch("du", "--json", "small")), &report); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	want := duReport{
+		Projects: []duProject{{Name: "2026-02-28-small", Path: filepath.Join(root, "2026-02-28-small"), Size: 13, Files: 1, Biggest: []dirSize{}}},
+		Size:     13,
+		Files:    1,
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Fatalf("json report = %+v, want %+v", report, want)
+	}
+
+	if err := run([]string{"du", "--sort", "weight"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil {
+		t.Fatal("expected error for an unknown sort")
+	}
+}
+
+func TestRunDuLinkedProject(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	site := filepath.Join(t.TempDir(), "site")
+	writeTree(t, site, map[string]string{"index.html": strings.Repeat("x", 1024), "assets/app.css": "body{}"})
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatalf("create hatchery: %v", err)
+	}
+	if err := os.Symlink(site, filepath.Join(root, "2026-02-28-site")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"du", "--json"}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("du returned error: %v", err)
+	}
+	var report duReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("parse json: %v", err)
+	}
+	if len(report.Projects) != 1 || report.Size != 1030 || report.Files != 2 {
+		t.Fatalf("expected the link target to be measured, got %+v", report)
+	}
+}
+
+func TestScanProjectsCachesByDirMtime(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "2026-02-28-api")
+	writeTree(t, project, map[string]string{"a": "12345"})
+
+	var walks atomic.Int32
+	previous := projectStatsFn
+	projectStatsFn = func(path string) projectStats {
+		walks.Add(1)
+		return walkProjectStats(path)
+	}
+	t.Cleanup(func() { projectStatsFn = previous })
+
+	if stats := scanProjects(root, []string{project}, true); stats[project].Size != 5 {
+		t.Fatalf("first scan = %+v", stats[project])
+	}
+	if stats := scanProjects(root, []string{project}, true); stats[project].Size != 5 || walks.Load() != 1 {
+		t.Fatalf("expected a cache hit, walks = %d", walks.Load())
+	}
+	scanProjects(root, []string{project}, false)
+	if walks.Load() != 2 {
+		t.Fatalf("expected an uncached scan to walk, walks = %d", walks.Load())
+	}
+
+	writeTree(t, project, map[string]string{"b": "678"})
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(project, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if stats := scanProjects(root, []string{project}, true); stats[project].Size != 8 || walks.Load() != 3 {
+		t.Fatalf("expected a changed directory to be walked again, got %+v after %d walks", stats[project], walks.Load())
+	}
+
+	cache := loadStatsCache(root)
+	entry := cache[filepath.Base(project)]
+	entry.Scanned = entry.Scanned.Add(-statsCacheTTL)
+	cache[filepath.Base(project)] = entry
+	if err := saveStatsCache(root, cache); err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+	if scanProjects(root, []string{project}, true); walks.Load() != 4 {
+		t.Fatalf("expected an expired entry to be walked again, walks = %d", walks.Load())
+	}
+}
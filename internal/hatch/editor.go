@@ -0,0 +1,121 @@
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var startEditorFn = startEditor
+var editorLookPathFn = exec.LookPath
+
+type editorMode string
+
+const (
+	// editorAuto detaches known GUI editors and runs everything else in
+	// the terminal.
+	editorAuto     editorMode = "auto"
+	editorDetach   editorMode = "detach"
+	editorTerminal editorMode = "terminal"
+)
+
+type editorConfig struct {
+	// Command launches the editor, e.g. "code" or "nvim -O"; the project
+	// path is appended.
+	Command string `json:"command"`
+	// Mode is auto, detach, or terminal.
+	Mode string `json:"mode"`
+}
+
+// editorFallbacks are tried on PATH when neither the project, the config,
+// $VISUAL, nor $EDITOR names an editor.
+var editorFallbacks = []string{"code", "cursor", "zed", "nvim", "vim", "vi"}
+
+// guiEditors are launchers that open their own window and should not hold
+// on to the terminal.
+var guiEditors = map[string]bool{
+	"code": true, "code-insiders": true, "codium": true, "cursor": true, "zed": true, "subl": true,
+	"idea": true, "goland": true, "pycharm": true, "webstorm": true, "clion": true, "rustrover": true,
+	"rider": true, "phpstorm": true, "rubymine": true, "datagrip": true, "fleet": true, "studio": true,
+}
+
+type editorCommand struct {
+	args   []string
+	detach bool
+}
+
+// resolveEditor picks the editor for a project: the project's metadata
+// override, then the config, then $VISUAL, $EDITOR, and finally the first
+// of editorFallbacks found on PATH.
+func resolveEditor(cfg editorConfig, meta projectMeta, getenv func(string) string) (editorCommand, error) {
+	command := ""
+	for _, candidate := range []string{meta.Editor, cfg.Command, getenv("VISUAL"), getenv("EDITOR")} {
+		if strings.TrimSpace(candidate) != "" {
+			command = candidate
+			break
+		}
+	}
+	if command == "" {
+		for _, candidate := range editorFallbacks {
+			if _, err := editorLookPathFn(candidate); err == nil {
+				command = candidate
+				break
+			}
+		}
+	}
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return editorCommand{}, errors.New("no editor found: set $VISUAL or editor.command in the hatch config")
+	}
+
+	mode := editorMode(strings.ToLower(strings.TrimSpace(cfg.Mode)))
+	switch mode {
+	case "", editorAuto:
+		name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+		name = strings.TrimSuffix(name, ".sh")
+		return editorCommand{args: args, detach: guiEditors[name]}, nil
+	case editorDetach:
+		return editorCommand{args: args, detach: true}, nil
+	case editorTerminal:
+		return editorCommand{args: args}, nil
+	default:
+		return editorCommand{}, fmt.Errorf("config editor.mode: unknown mode %q (use auto, detach, or terminal)", cfg.Mode)
+	}
+}
+
+// openInEditor launches the resolved editor on projectPath. Detached editors
+// are started in their own session and left running; terminal editors take
+// over in, out, and errOut until they exit.
+func openInEditor(root string, cfg config, projectPath string, in io.Reader, out, errOut io.Writer) error {
+	md, err := loadMetadata(root)
+	if err != nil {
+		return err
+	}
+	editor, err := resolveEditor(cfg.Editor, md.project(projectPath), os.Getenv)
+	if err != nil {
+		return err
+	}
+	return startEditorFn(editor, projectPath, in, out, errOut)
+}
+
+func startEditor(editor editorCommand, projectPath string, in io.Reader, out, errOut io.Writer) error {
+	cmd := exec.Command(editor.args[0], append(editor.args[1:], projectPath)...)
+	cmd.Dir = projectPath
+	if editor.detach {
+		detachProcess(cmd)
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("start editor %s: %w", editor.args[0], err)
+		}
+		return cmd.Process.Release()
+	}
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = in, out, errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run editor %s: %w", editor.args[0], err)
+	}
+	return nil
+}
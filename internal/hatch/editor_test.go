@@ -0,0 +1,170 @@
+package hatch
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveEditor(t *testing.T) {
+	original := editorLookPathFn
+	editorLookPathFn = func(name string) (string, error) {
+		if name == "zed" {
+			return "/usr/bin/zed", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	t.Cleanup(func() { editorLookPathFn = original })
+
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+	tests := []struct {
+		name    string
+		cfg     editorConfig
+		meta    projectMeta
+		env     map[string]string
+		want    editorCommand
+		wantErr bool
+	}{
+		{name: "project override wins", cfg: editorConfig{Command: "nvim"}, meta: projectMeta{Editor: "goland"}, env: map[string]string{"VISUAL": "vim"}, want: editorCommand{args: []string{"goland"}, detach: true}},
+		{name: "config before environment", cfg: editorConfig{Command: "code --new-window"}, env: map[string]string{"VISUAL": "vim"}, want: editorCommand{args: []string{"code", "--new-window"}, detach: true}},
+		{name: "visual before editor", env: map[string]string{"VISUAL": "nvim", "EDITOR": "nano"}, want: editorCommand{args: []string{"nvim"}}},
+		{name: "editor", env: map[string]string{"EDITOR": "/opt/homebrew/bin/hx"}, want: editorCommand{args: []string{"/opt/homebrew/bin/hx"}}},
+		{name: "path fallback", want: editorCommand{args: []string{"zed"}, detach: true}},
+		{name: "forced terminal", cfg: editorConfig{Command: "code", Mode: "terminal"}, want: editorCommand{args: []string{"code"}}},
+		{name: "forced detach", cfg: editorConfig{Command: "emacs", Mode: "detach"}, want: editorCommand{args: []string{"emacs"}, detach: true}},
+		{name: "unknown mode", cfg: editorConfig{Command: "code", Mode: "popup"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveEditor(tc.cfg, tc.meta, env(tc.env))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveEditor returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("resolveEditor = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	editorLookPathFn = func(string) (string, error) { return "", exec.ErrNotFound }
+	if _, err := resolveEditor(editorConfig{}, projectMeta{}, env(nil)); err == nil {
+		t.Fatalf("expected an error when no editor is available")
+	}
+}
+
+func TestStartEditorRunsInProjectDirectory(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	project := t.TempDir()
+	script := `pwd > opened; printf '%s' "$1" >> opened; read line; printf '%s' "$line"`
+
+	out := new(bytes.Buffer)
+	editor := editorCommand{args: []string{"sh", "-c", script, "sh"}}
+	if err := startEditor(editor, project, strings.NewReader("typed\n"), out, io.Discard); err != nil {
+		t.Fatalf("startEditor returned error: %v", err)
+	}
+	if out.String() != "typed" {
+		t.Fatalf("terminal editor output = %q, want it to read from the terminal", out.String())
+	}
+	opened, err := os.ReadFile(filepath.Join(project, "opened"))
+	if err != nil {
+		t.Fatalf("read marker: %v", err)
+	}
+	if lines := strings.Split(string(opened), "\n"); len(lines) != 2 || lines[1] != project {
+		t.Fatalf("editor saw %q, want cwd and argument %q", opened, project)
+	}
+}
+
+func TestRunEditUsesProjectOverride(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"editor": {"command": "code"}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+	if err := os.MkdirAll(filepath.Join(root, stateDirName), 0o755); err != nil {
+		t.Fatalf("create state dir: %v", err)
+	}
+	md := `{"projects": {"2026-02-28-api": {"editor": "goland"}}}`
+	if err := os.WriteFile(metadataPath(root), []byte(md), 0o644); err != nil {
+		t.Fatalf("write metadata: %v", err)
+	}
+
+	var launched []string
+	original := startEditorFn
+	startEditorFn = func(editor editorCommand, projectPath string, _ io.Reader, _, _ io.Writer) error {
+		launched = append(append([]string{}, editor.args...), projectPath)
+		if !editor.detach {
+			return errors.New("expected a detached GUI editor")
+		}
+		return nil
+	}
+	t.Cleanup(func() { startEditorFn = original })
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"--edit", "api"}, strings.NewReader(""), out, io.Discard, fixedNow); err != nil {
+		t.Fatalf("run --edit returned error: %v", err)
+	}
+	want := []string{"goland", filepath.Join(root, "2026-02-28-api")}
+	if !reflect.DeepEqual(launched, want) {
+		t.Fatalf("launched %v, want %v", launched, want)
+	}
+
+	launched = nil
+	if err := run([]string{"--edit", "--on-exists", "suffix", "web"}, strings.NewReader(""), out, io.Discard, fixedNow); err != nil {
+		t.Fatalf("run --edit returned error: %v", err)
+	}
+	if len(launched) == 0 || launched[0] != "code" {
+		t.Fatalf("launched %v, want the configured editor", launched)
+	}
+}
+
+func TestEnterUsesOverrideFromProjectSpace(t *testing.T) {
+	root, work := t.TempDir(), t.TempDir()
+	project := filepath.Join(work, "2026-02-28-api")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	err := updateMetadata(work, func(md *metadata) error {
+		meta := md.project(project)
+		meta.Editor = "goland"
+		md.setProject(project, meta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("set editor: %v", err)
+	}
+
+	var launched []string
+	original := startEditorFn
+	startEditorFn = func(editor editorCommand, projectPath string, _ io.Reader, _, _ io.Writer) error {
+		launched = editor.args
+		return nil
+	}
+	t.Cleanup(func() { startEditorFn = original })
+
+	// A project picked in the all-spaces view lives outside c.root.
+	c := commandContext{root: root, cfg: config{Editor: editorConfig{Command: "code"}}, in: strings.NewReader(""), out: io.Discard, errOut: io.Discard, now: fixedNow}
+	if err := c.enter(project, "Opened", browserSelection{Edit: true}); err != nil {
+		t.Fatalf("enter returned error: %v", err)
+	}
+	if !reflect.DeepEqual(launched, []string{"goland"}) {
+		t.Fatalf("launched %v, want the work space's override", launched)
+	}
+}
@@ -0,0 +1,337 @@
+package hatch
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// projectEnvFile is where a project declares its own environment, relative
+// to the project directory.
+var projectEnvFile = filepath.Join(".hatch", "env")
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type envConfig struct {
+	// Enabled applies per-project environments when entering a project.
+	Enabled bool `json:"enabled"`
+}
+
+// projectEnv is what entering a project sets up. Values may refer to
+// environment variables as $NAME or ${NAME}; $HATCH_PROJECT is the project
+// directory.
+type projectEnv struct {
+	// Vars are exported as given.
+	Vars map[string]string `json:"vars,omitempty"`
+	// Path entries are prepended to PATH, relative to the project.
+	Path []string `json:"path,omitempty"`
+	// Activate is a script to source, such as .venv/bin/activate. Shells
+	// other than bash and zsh use the sibling with their own extension.
+	Activate string `json:"activate,omitempty"`
+}
+
+// parseEnvFile reads a .hatch/env file. Each line is NAME=value, "path
+// <dir>", or "activate <script>"; blank lines and lines starting with # are
+// skipped.
+func parseEnvFile(data []byte) (projectEnv, error) {
+	var env projectEnv
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if dir, ok := strings.CutPrefix(line, "path "); ok {
+			env.Path = append(env.Path, strings.TrimSpace(dir))
+			continue
+		}
+		if script, ok := strings.CutPrefix(line, "activate "); ok {
+			env.Activate = strings.TrimSpace(script)
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(strings.TrimPrefix(name, "export "))
+		if !ok || !envNamePattern.MatchString(name) {
+			return env, fmt.Errorf("line %d: want NAME=value, path <dir>, or activate <script>", number)
+		}
+		if env.Vars == nil {
+			env.Vars = make(map[string]string)
+		}
+		env.Vars[name] = unquote(strings.TrimSpace(value))
+	}
+	if err := scanner.Err(); err != nil {
+		return env, err
+	}
+	return env, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func envFileHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadProjectEnv returns the environments declared for the project at path:
+// the metadata env, then its .hatch/env file if that has been allowed. A
+// file that is new or changed since hatch allow is skipped, and notice says
+// how to allow it.
+func loadProjectEnv(meta projectMeta, projectPath string) (envs []projectEnv, notice string, err error) {
+	if meta.Env != nil {
+		envs = append(envs, *meta.Env)
+	}
+	data, err := os.ReadFile(filepath.Join(projectPath, projectEnvFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return envs, "", nil
+	}
+	if err != nil {
+		return envs, "", fmt.Errorf("read %s: %w", projectEnvFile, err)
+	}
+	if meta.EnvAllowed != envFileHash(data) {
+		state := "is not allowed"
+		if meta.EnvAllowed != "" {
+			state = "changed since it was allowed"
+		}
+		return envs, fmt.Sprintf("%s %s; review it, then run: hatch allow %s", projectEnvFile, state, filepath.Base(projectPath)), nil
+	}
+	env, err := parseEnvFile(data)
+	if err != nil {
+		return envs, "", fmt.Errorf("parse %s: %w", filepath.Join(projectPath, projectEnvFile), err)
+	}
+	if err := checkActivateInside(projectPath, env.Activate); err != nil {
+		return envs, "", err
+	}
+	return append(envs, env), "", nil
+}
+
+// checkActivateInside rejects an activate script from .hatch/env that lies
+// outside the project. hatch allow only hashes .hatch/env itself, so a
+// script elsewhere could change without another allow. Only $HATCH_PROJECT
+// may be used, and symlinks are followed before checking.
+func checkActivateInside(projectPath, script string) error {
+	if script == "" {
+		return nil
+	}
+	var unknown string
+	path := os.Expand(script, func(name string) string {
+		if name == "HATCH_PROJECT" {
+			return projectPath
+		}
+		unknown = name
+		return ""
+	})
+	if unknown != "" {
+		return fmt.Errorf("activate %s: only $HATCH_PROJECT may be used in %s", script, projectEnvFile)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+	if !inside(path, projectPath) {
+		return fmt.Errorf("activate %s: script must be inside the project", script)
+	}
+	return nil
+}
+
+// addEnv appends the actions that set up env in shell and returns a short
+// description of each, in order.
+func (a *shellActions) addEnv(projectPath, shell string, env projectEnv, getenv func(string) string) ([]string, error) {
+	expand := func(value string) string {
+		return os.Expand(value, func(name string) string {
+			if name == "HATCH_PROJECT" {
+				return projectPath
+			}
+			return getenv(name)
+		})
+	}
+	resolve := func(path string) string {
+		path = expand(path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectPath, path)
+		}
+		return path
+	}
+
+	var described []string
+	names := make([]string, 0, len(env.Vars))
+	for name := range env.Vars {
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid environment variable name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a.setenv(name, expand(env.Vars[name]))
+		described = append(described, name)
+	}
+
+	if len(env.Path) > 0 {
+		dirs := make([]string, 0, len(env.Path)+1)
+		for _, dir := range env.Path {
+			dirs = append(dirs, resolve(dir))
+		}
+		if current := getenv("PATH"); current != "" {
+			dirs = append(dirs, current)
+		}
+		a.setenv("PATH", strings.Join(dirs, string(os.PathListSeparator)))
+		described = append(described, "PATH+="+strings.Join(env.Path, string(os.PathListSeparator)))
+	}
+
+	if env.Activate != "" {
+		script, err := activationScript(resolve(env.Activate), shell)
+		if err != nil {
+			return described, err
+		}
+		a.source(script)
+		rel, err := filepath.Rel(projectPath, script)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = script
+		}
+		described = append(described, "source "+rel)
+	}
+	return described, nil
+}
+
+// activationExts lists script extensions each shell can source, preferred
+// first. nushell is missing because it cannot source a file chosen at run
+// time.
+var activationExts = map[string][]string{
+	"zsh":    {"", ".sh", ".zsh"},
+	"bash":   {"", ".sh", ".bash"},
+	"fish":   {".fish"},
+	"pwsh":   {".ps1"},
+	"elvish": {".elv"},
+	"xonsh":  {".xsh"},
+}
+
+// activationScript picks the variant of script written for shell: script
+// itself when its extension fits, otherwise a sibling such as activate.fish
+// next to .venv/bin/activate. venv's PowerShell script is Activate.ps1.
+func activationScript(script, shell string) (string, error) {
+	exts, ok := activationExts[shell]
+	if !ok {
+		return "", fmt.Errorf("cannot source activation scripts in %s", shellName(shell))
+	}
+	stem, scriptExt := script, ""
+	for _, known := range []string{".sh", ".bash", ".zsh", ".fish", ".ps1", ".elv", ".xsh", ".nu"} {
+		if strings.HasSuffix(script, known) {
+			stem, scriptExt = strings.TrimSuffix(script, known), known
+			break
+		}
+	}
+	var candidates []string
+	for _, ext := range exts {
+		if ext == scriptExt {
+			candidates = append(candidates, script)
+		}
+	}
+	for _, ext := range exts {
+		candidates = append(candidates, stem+ext)
+	}
+	if shell == "pwsh" {
+		candidates = append(candidates, filepath.Join(filepath.Dir(script), "Activate.ps1"))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no %s activation script for %s", shellName(shell), script)
+}
+
+func shellName(shell string) string {
+	if shell == "" {
+		return "this shell"
+	}
+	return shell
+}
+
+// addProjectEnv adds the project's environment to actions when env
+// activation is enabled and the shell hook can apply it. It returns what
+// was set and anything skipped; neither stops the project from opening.
+func (c commandContext) addProjectEnv(actions *shellActions, projectPath string) (applied, warnings []string) {
+	if !c.cfg.Env.Enabled || c.options.cwdFile == "" || c.options.hookVersion < 2 {
+		return nil, nil
+	}
+	md, err := loadMetadata(filepath.Dir(filepath.Clean(projectPath)))
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	envs, notice, err := loadProjectEnv(md.project(projectPath), projectPath)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	if notice != "" {
+		warnings = append(warnings, notice)
+	}
+	for _, env := range envs {
+		described, err := actions.addEnv(projectPath, c.options.hookShell, env, os.Getenv)
+		applied = append(applied, described...)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+	}
+	return applied, warnings
+}
+
+// runAllow approves the project's current .hatch/env file, direnv style.
+func runAllow(c commandContext, args []string) error {
+	project, err := projectArg(c, "allow", args)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(project.Path, projectEnvFile))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", projectEnvFile, err)
+	}
+	env, err := parseEnvFile(data)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", filepath.Join(project.Path, projectEnvFile), err)
+	}
+	if err := checkActivateInside(project.Path, env.Activate); err != nil {
+		return err
+	}
+	err = updateMetadata(filepath.Dir(project.Path), func(md *metadata) error {
+		meta := md.project(project.Path)
+		meta.EnvAllowed = envFileHash(data)
+		md.setProject(project.Path, meta)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render("Allowed "+projectEnvFile+" for "+project.Name))
+	return nil
+}
+
+// runDeny revokes an earlier hatch allow.
+func runDeny(c commandContext, args []string) error {
+	project, err := projectArg(c, "deny", args)
+	if err != nil {
+		return err
+	}
+	err = updateMetadata(filepath.Dir(project.Path), func(md *metadata) error {
+		meta := md.project(project.Path)
+		meta.EnvAllowed = ""
+		md.setProject(project.Path, meta)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render("Denied "+projectEnvFile+" for "+project.Name))
+	return nil
+}
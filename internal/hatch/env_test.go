@@ -0,0 +1,238 @@
+package hatch
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	t.Parallel()
+
+	data := `# tools for this spike
+GOFLAGS=-mod=mod
+export NODE_ENV = "development"
+GREETING='hello world'
+path bin
+path node_modules/.bin
+activate .venv/bin/activate
+`
+	env, err := parseEnvFile([]byte(data))
+	if err != nil {
+		t.Fatalf("parseEnvFile returned error: %v", err)
+	}
+	want := projectEnv{
+		Vars:     map[string]string{"GOFLAGS": "-mod=mod", "NODE_ENV": "development", "GREETING": "hello world"},
+		Path:     []string{"bin", "node_modules/.bin"},
+		Activate: ".venv/bin/activate",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Fatalf("env = %+v, want %+v", env, want)
+	}
+
+	if _, err := parseEnvFile([]byte("OK=1\nnot a declaration\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected line 2 error, got %v", err)
+	}
+}
+
+func TestActivationScript(t *testing.T) {
+	t.Parallel()
+
+	bin := filepath.Join(t.TempDir(), ".venv", "bin")
+	writeTree(t, bin, map[string]string{
+		"activate":      "",
+		"activate.fish": "",
+		"Activate.ps1":  "",
+	})
+	script := filepath.Join(bin, "activate")
+	tests := map[string]string{
+		"bash": script,
+		"zsh":  script,
+		"fish": script + ".fish",
+		"pwsh": filepath.Join(bin, "Activate.ps1"),
+	}
+	for shell, want := range tests {
+		got, err := activationScript(script, shell)
+		if err != nil || got != want {
+			t.Fatalf("activationScript(%s) = %q, %v; want %q", shell, got, err, want)
+		}
+	}
+	if got, err := activationScript(script+".fish", "bash"); err != nil || got != script {
+		t.Fatalf("activationScript(activate.fish, bash) = %q, %v; want %q", got, err, script)
+	}
+	if _, err := activationScript(script, "xonsh"); err == nil {
+		t.Fatal("expected error without an xonsh script")
+	}
+	if _, err := activationScript(script, "nu"); err == nil {
+		t.Fatal("expected error for nushell")
+	}
+}
+
+func TestCheckActivateInside(t *testing.T) {
+	t.Parallel()
+
+	base := t.TempDir()
+	project := filepath.Join(base, "2026-01-10-api")
+	writeTree(t, project, map[string]string{".venv/bin/activate": ""})
+	writeTree(t, base, map[string]string{"elsewhere/activate": ""})
+	if err := os.Symlink(filepath.Join(base, "elsewhere", "activate"), filepath.Join(project, "linked")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	for _, script := range []string{"", ".venv/bin/activate", "$HATCH_PROJECT/.venv/bin/activate", filepath.Join(project, ".venv", "bin", "activate")} {
+		if err := checkActivateInside(project, script); err != nil {
+			t.Fatalf("checkActivateInside(%q) = %v, want nil", script, err)
+		}
+	}
+	for _, script := range []string{"../elsewhere/activate", filepath.Join(base, "elsewhere", "activate"), "$HOME/activate", "linked"} {
+		if err := checkActivateInside(project, script); err == nil {
+			t.Fatalf("checkActivateInside(%q) accepted a script outside the project", script)
+		}
+	}
+}
+
+func TestShellActionsAddEnv(t *testing.T) {
+	t.Parallel()
+
+	project := t.TempDir()
+	writeTree(t, project, map[string]string{".venv/bin/activate": ""})
+	getenv := func(name string) string {
+		return map[string]string{"PATH": "/usr/bin", "HOME": "/home/me"}[name]
+	}
+	env := projectEnv{
+		Vars:     map[string]string{"GOPATH": "$HATCH_PROJECT/go", "CACHE": "${HOME}/.cache"},
+		Path:     []string{"bin"},
+		Activate: ".venv/bin/activate",
+	}
+
+	var actions shellActions
+	described, err := actions.addEnv(project, "bash", env, getenv)
+	if err != nil {
+		t.Fatalf("addEnv returned error: %v", err)
+	}
+	wantDescribed := []string{"CACHE", "GOPATH", "PATH+=bin", "source " + filepath.Join(".venv", "bin", "activate")}
+	if !reflect.DeepEqual(described, wantDescribed) {
+		t.Fatalf("described = %q, want %q", described, wantDescribed)
+	}
+	wantLines := [][]string{
+		{"set", "CACHE", "/home/me/.cache"},
+		{"set", "GOPATH", project + "/go"},
+		{"set", "PATH", filepath.Join(project, "bin") + string(os.PathListSeparator) + "/usr/bin"},
+		{"source", filepath.Join(project, ".venv", "bin", "activate")},
+	}
+	if !reflect.DeepEqual(actions.lines, wantLines) {
+		t.Fatalf("actions = %q, want %q", actions.lines, wantLines)
+	}
+}
+
+func TestRunAppliesAllowedProjectEnv(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"env": {"enabled": true}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+	project := filepath.Join(root, "2026-01-10-api")
+	writeTree(t, project, map[string]string{".hatch/env": "APP_ENV=dev\n"})
+
+	cwdFile := filepath.Join(t.TempDir(), "cwd")
+	enter := func() (string, string, string) {
+		t.Helper()
+		out, errOut := new(bytes.Buffer), new(bytes.Buffer)
+		args := []string{"--cwd-file", cwdFile, "--hook-version", "2", "--hook-shell", "zsh", "--on-exists", "open", "2026-01-10-api"}
+		if err := run(args, strings.NewReader(""), out, errOut, fixedNow); err != nil {
+			t.Fatalf("run returned error: %v", err)
+		}
+		actions, _ := os.ReadFile(cwdFile)
+		return string(actions), out.String(), errOut.String()
+	}
+
+	actions, _, errOut := enter()
+	if strings.Contains(actions, "APP_ENV") || !strings.Contains(errOut, "hatch allow 2026-01-10-api") {
+		t.Fatalf("unallowed env applied or not reported: actions %q, stderr %q", actions, errOut)
+	}
+
+	if err := run([]string{"allow", "api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("allow returned error: %v", err)
+	}
+	actions, out, errOut := enter()
+	if !strings.Contains(actions, "set\tAPP_ENV\tdev\n") || !strings.Contains(out, "Env: APP_ENV") || errOut != "" {
+		t.Fatalf("allowed env not applied: actions %q, stdout %q, stderr %q", actions, out, errOut)
+	}
+
+	writeTree(t, project, map[string]string{".hatch/env": "APP_ENV=prod\n"})
+	actions, _, errOut = enter()
+	if strings.Contains(actions, "APP_ENV") || !strings.Contains(errOut, "changed since it was allowed") {
+		t.Fatalf("changed env applied or not reported: actions %q, stderr %q", actions, errOut)
+	}
+
+	if err := run([]string{"deny", project}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("deny returned error: %v", err)
+	}
+	md, err := loadMetadata(root)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta := md.project(project); meta.EnvAllowed != "" || meta.Visits != 3 {
+		t.Fatalf("expected deny to clear only the approval, got %+v", meta)
+	}
+}
+
+func TestRunSkipsProjectEnvUnlessEnabled(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	project := filepath.Join(root, "2026-01-10-api")
+	writeTree(t, project, map[string]string{"keep": ""})
+	err := updateMetadata(root, func(md *metadata) error {
+		md.setProject(project, projectMeta{Env: &projectEnv{Vars: map[string]string{"APP_ENV": "dev"}}})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update metadata: %v", err)
+	}
+
+	cwdFile := filepath.Join(t.TempDir(), "cwd")
+	args := []string{"--cwd-file", cwdFile, "--hook-version", "2", "--hook-shell", "bash", "--on-exists", "open", "2026-01-10-api"}
+	if err := run(args, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if actions, _ := os.ReadFile(cwdFile); strings.Contains(string(actions), "APP_ENV") {
+		t.Fatalf("env applied without env.enabled: %q", actions)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"env": {"enabled": true}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+	if err := run(args, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if actions, _ := os.ReadFile(cwdFile); !strings.Contains(string(actions), "set\tAPP_ENV\tdev") {
+		t.Fatalf("metadata env should apply without allow: %q", actions)
+	}
+}
+
+func TestRunAllowRequiresEnvFile(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	writeTree(t, filepath.Join(root, "2026-01-10-api"), map[string]string{"keep": ""})
+
+	err := run([]string{"allow", "api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("allow without .hatch/env error = %v, want not exist", err)
+	}
+	writeTree(t, filepath.Join(root, "2026-01-10-api"), map[string]string{".hatch/env": "activate ../../outside/activate\n"})
+	if err := run([]string{"allow", "api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil || !strings.Contains(err.Error(), "inside the project") {
+		t.Fatalf("allow with an activate script outside the project error = %v", err)
+	}
+	err = run([]string{"allow", "missing"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow)
+	if !errors.Is(err, errProjectNotFound) {
+		t.Fatalf("allow missing project error = %v, want errProjectNotFound", err)
+	}
+}
@@ -0,0 +1,108 @@
+package hatch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookProtocol is the version of the --cwd-file protocol this binary
+// writes. Version 1 hooks pass only --cwd-file and read the file as a bare
+// path to cd into. Version 2 hooks also pass --hook-version and
+// --hook-shell and run one action per line: "cd\t<dir>",
+// "set\t<name>\t<value>", or "source\t<script>".
+const hookProtocol = 2
+
+// shellActions collects what the shell hook should do once hatch exits.
+// Only navigating commands add actions; everything else leaves the file
+// empty and the shell untouched.
+type shellActions struct {
+	lines [][]string
+}
+
+func (a *shellActions) cd(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve path for cwd file: %w", err)
+	}
+	a.lines = append(a.lines, []string{"cd", abs})
+	return nil
+}
+
+func (a *shellActions) setenv(name, value string) {
+	a.lines = append(a.lines, []string{"set", name, value})
+}
+
+// source asks the hook to source script, which must be written in the
+// language of the hook's shell.
+func (a *shellActions) source(script string) {
+	a.lines = append(a.lines, []string{"source", script})
+}
+
+// write stores the actions in cwdFile for a hook speaking version. Version 1
+// hooks only understand a directory, so they get the last cd and nothing
+// else.
+func (a *shellActions) write(cwdFile string, version int) error {
+	if strings.TrimSpace(cwdFile) == "" || len(a.lines) == 0 {
+		return nil
+	}
+	var content strings.Builder
+	for _, line := range a.lines {
+		if version < 2 {
+			if line[0] == "cd" {
+				content.Reset()
+				content.WriteString(line[1])
+			}
+			continue
+		}
+		for _, field := range line {
+			if strings.ContainsAny(field, "\n\r") {
+				return fmt.Errorf("cannot pass %q to the shell: it contains a line break", field)
+			}
+		}
+		content.WriteString(strings.Join(line, "\t") + "\n")
+	}
+	if err := os.WriteFile(cwdFile, []byte(content.String()), 0o644); err != nil {
+		return fmt.Errorf("write cwd file: %w", err)
+	}
+	return nil
+}
+
+// hookWarning explains how to update a shell hook older than this binary,
+// or returns "" when the hook is current or hatch runs without one.
+func hookWarning(options cliOptions, getenv func(string) string) string {
+	if options.cwdFile == "" || options.hookVersion >= hookProtocol {
+		return ""
+	}
+	installed := options.hookVersion
+	if installed == 0 {
+		installed = 1
+	}
+	shell := options.hookShell
+	if shell == "" {
+		shell = filepath.Base(getenv("SHELL"))
+	}
+	return fmt.Sprintf("warning: your hatch shell hook is out of date (protocol %d, hatch %s uses %d); reload it with: %s",
+		installed, version, hookProtocol, initCommand(shell))
+}
+
+// initCommand is the line that loads the hook in shell's startup file.
+func initCommand(shell string) string {
+	switch shell {
+	case "zsh", "bash":
+		return `eval "$(hatch --init ` + shell + `)"`
+	case "fish":
+		return "hatch --init fish | source"
+	case "nu":
+		return "hatch --init nu | save -f ~/.config/nushell/hatch.nu"
+	case "pwsh":
+		return "Invoke-Expression (& hatch --init pwsh | Out-String)"
+	case "elvish":
+		return "eval (hatch --init elvish | slurp)"
+	case "xonsh":
+		return "execx($(hatch --init xonsh))"
+	default:
+		return "hatch --init <shell>"
+	}
+}
@@ -0,0 +1,158 @@
+package hatch
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestShellActionsWrite(t *testing.T) {
+	t.Parallel()
+
+	var actions shellActions
+	if err := actions.cd("/tmp/first"); err != nil {
+		t.Fatalf("cd: %v", err)
+	}
+	actions.setenv("VIRTUAL_ENV", "/tmp/first/.venv")
+	actions.source("/tmp/first/.hatch/env.sh")
+	if err := actions.cd("/tmp/second"); err != nil {
+		t.Fatalf("cd: %v", err)
+	}
+
+	dir := t.TempDir()
+	current := filepath.Join(dir, "current")
+	if err := actions.write(current, hookProtocol); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, _ := os.ReadFile(current)
+	want := "cd\t/tmp/first\nset\tVIRTUAL_ENV\t/tmp/first/.venv\nsource\t/tmp/first/.hatch/env.sh\ncd\t/tmp/second\n"
+	if string(got) != want {
+		t.Fatalf("v2 actions = %q, want %q", got, want)
+	}
+
+	legacy := filepath.Join(dir, "legacy")
+	if err := actions.write(legacy, 0); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got, _ := os.ReadFile(legacy); string(got) != "/tmp/second" {
+		t.Fatalf("v1 file = %q, want the last directory", got)
+	}
+
+	actions.setenv("BROKEN", "two\nlines")
+	if err := actions.write(filepath.Join(dir, "broken"), hookProtocol); err == nil {
+		t.Fatal("expected error for a value with a line break")
+	}
+}
+
+func TestShellActionsWriteNothingWithoutActions(t *testing.T) {
+	t.Parallel()
+
+	cwdFile := filepath.Join(t.TempDir(), "cwd")
+	if err := os.WriteFile(cwdFile, nil, 0o644); err != nil {
+		t.Fatalf("create cwd file: %v", err)
+	}
+	var actions shellActions
+	if err := actions.write(cwdFile, hookProtocol); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if info, err := os.Stat(cwdFile); err != nil || info.Size() != 0 {
+		t.Fatalf("expected empty cwd file, got %v, %v", info, err)
+	}
+}
+
+func TestHookWarning(t *testing.T) {
+	t.Parallel()
+
+	getenv := func(key string) string {
+		if key == "SHELL" {
+			return "/usr/bin/fish"
+		}
+		return ""
+	}
+	tests := []struct {
+		options cliOptions
+		want    string
+	}{
+		{cliOptions{}, ""},
+		{cliOptions{cwdFile: "/tmp/x", hookVersion: hookProtocol}, ""},
+		{cliOptions{cwdFile: "/tmp/x"}, "protocol 1"},
+		{cliOptions{cwdFile: "/tmp/x"}, "hatch --init fish | source"},
+		{cliOptions{cwdFile: "/tmp/x", hookVersion: 1, hookShell: "zsh"}, `eval "$(hatch --init zsh)"`},
+	}
+	for _, tt := range tests {
+		got := hookWarning(tt.options, getenv)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Fatalf("hookWarning(%+v) = %q, want it to contain %q", tt.options, got, tt.want)
+		}
+	}
+}
+
+func TestRunWritesActionsForCurrentHook(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	cwdFile := filepath.Join(t.TempDir(), "cwd")
+
+	errOut := new(bytes.Buffer)
+	args := []string{"--cwd-file", cwdFile, "--hook-version", "2", "--hook-shell", "zsh", "api"}
+	if err := run(args, strings.NewReader(""), new(bytes.Buffer), errOut, fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	got, _ := os.ReadFile(cwdFile)
+	if want := "cd\t" + filepath.Join(root, "2026-02-28-api") + "\n"; string(got) != want {
+		t.Fatalf("cwd file = %q, want %q", got, want)
+	}
+	if errOut.Len() != 0 {
+		t.Fatalf("expected no warning, got %q", errOut.String())
+	}
+
+	errOut.Reset()
+	if err := run([]string{"--cwd-file", cwdFile, "web"}, strings.NewReader(""), new(bytes.Buffer), errOut, fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "shell hook is out of date") {
+		t.Fatalf("expected upgrade warning, got %q", errOut.String())
+	}
+}
+
+func TestPosixHookRunsActions(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("bash not available")
+	}
+	project := t.TempDir()
+	activate := filepath.Join(t.TempDir(), "activate.sh")
+	if err := os.WriteFile(activate, []byte("SOURCED=yes\n"), 0o644); err != nil {
+		t.Fatalf("write activate script: %v", err)
+	}
+
+	// A stand-in hatch checks the hook's arguments and writes every action
+	// kind plus one the hook does not know.
+	binDir := t.TempDir()
+	fake := `#!/bin/sh
+[ "$1" = --cwd-file ] && [ "$3" = --hook-version ] && [ "$4" = 2 ] && [ "$6" = bash ] || exit 3
+printf 'cd\t%s\nset\tGREETING\thello  world\nsource\t%s\nlater\tignored\n' "$HATCH_TEST_PROJECT" "$HATCH_TEST_ACTIVATE" > "$2"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "hatch"), []byte(fake), 0o755); err != nil {
+		t.Fatalf("write fake hatch: %v", err)
+	}
+	script, err := shellInit("bash")
+	if err != nil {
+		t.Fatalf("shellInit: %v", err)
+	}
+	cmd := exec.Command("bash", "--norc", "--noprofile", "-c", script+"\nhatch && printf '%s|%s|%s' \"$PWD\" \"$GREETING\" \"$SOURCED\"")
+	cmd.Env = append(os.Environ(),
+		"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"HATCH_TEST_PROJECT="+project,
+		"HATCH_TEST_ACTIVATE="+activate,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, output)
+	}
+	if want := project + "|hello  world|yes"; string(output) != want {
+		t.Fatalf("hook result = %q, want %q", output, want)
+	}
+}
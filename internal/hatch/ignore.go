@@ -0,0 +1,277 @@
+package hatch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// hatchIgnoreFile is always honored by copies unless ignore rules are
+// disabled, regardless of which other ignore files are configured.
+const hatchIgnoreFile = ".hatchignore"
+
+var gitTrackedFilesFn = runGitTrackedFiles
+
+type ignoreRules struct {
+	// files names per-directory ignore files, such as ".gitignore".
+	files []string
+	// exclude holds gitignore-style patterns relative to the copy source.
+	exclude []string
+	// disabled copies everything, including .hatchignore matches.
+	disabled bool
+}
+
+type ignorePattern struct {
+	base     string
+	glob     string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// ignoreMatcher applies gitignore semantics to paths relative to a copy
+// source: the last matching pattern wins, patterns from deeper ignore files
+// are consulted after shallower ones, and tracked git files are never
+// ignored.
+type ignoreMatcher struct {
+	ctx      context.Context
+	source   string
+	rules    ignoreRules
+	patterns []ignorePattern
+	// excludes come from the command line and config and are applied after
+	// every ignore file, so a negation in .gitignore cannot undo them.
+	excludes []ignorePattern
+	ignored  map[string]bool
+	tracked  map[string]bool
+	loaded   bool
+}
+
+func newIgnoreMatcher(ctx context.Context, source string, rules ignoreRules) *ignoreMatcher {
+	m := &ignoreMatcher{
+		ctx:     ctx,
+		source:  source,
+		rules:   rules,
+		ignored: map[string]bool{},
+	}
+	for _, line := range rules.exclude {
+		if pattern, ok := parseIgnorePattern("", line); ok {
+			m.excludes = append(m.excludes, pattern)
+		}
+	}
+	return m
+}
+
+// enterDir loads the ignore files that live in rel, the slash-separated
+// directory path relative to the source.
+func (m *ignoreMatcher) enterDir(rel string) error {
+	if m.rules.disabled {
+		return nil
+	}
+	names := append([]string{}, m.rules.files...)
+	names = append(names, hatchIgnoreFile)
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(m.source, filepath.FromSlash(rel), name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read ignore file %s: %w", name, err)
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if pattern, ok := parseIgnorePattern(rel, scanner.Text()); ok {
+				m.patterns = append(m.patterns, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// skip reports whether rel should be left out of the copy. rel is
+// slash-separated and relative to the source.
+func (m *ignoreMatcher) skip(rel string, isDir bool) (bool, error) {
+	if m.rules.disabled {
+		return false, nil
+	}
+
+	ignored := m.ignored[path.Dir(rel)]
+	if !ignored {
+		ignored = m.matches(rel, isDir)
+	}
+	if !ignored {
+		return false, nil
+	}
+
+	if err := m.loadTracked(); err != nil {
+		return false, err
+	}
+	if !m.tracked[rel] {
+		return true, nil
+	}
+	if isDir {
+		// Descend to copy the tracked files, but keep treating everything
+		// else below this directory as ignored.
+		m.ignored[rel] = true
+	}
+	return false, nil
+}
+
+func (m *ignoreMatcher) matches(rel string, isDir bool) bool {
+	return matchPatterns(m.excludes, rel, isDir, matchPatterns(m.patterns, rel, isDir, false))
+}
+
+// matchPatterns applies patterns in order to rel, starting from ignored;
+// the last matching pattern wins.
+func matchPatterns(patterns []ignorePattern, rel string, isDir, ignored bool) bool {
+	for _, pattern := range patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		sub := rel
+		if pattern.base != "" {
+			if !strings.HasPrefix(rel, pattern.base+"/") {
+				continue
+			}
+			sub = strings.TrimPrefix(rel, pattern.base+"/")
+		}
+		target := sub
+		if !pattern.anchored {
+			target = path.Base(sub)
+		}
+		if matchIgnoreGlob(pattern.glob, target) {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
+
+// loadTracked asks git for tracked files the first time something is about
+// to be ignored, so sources without ignore matches never shell out.
+func (m *ignoreMatcher) loadTracked() error {
+	if m.loaded {
+		return nil
+	}
+	m.loaded = true
+	files, err := gitTrackedFilesFn(m.ctx, m.source)
+	if err != nil {
+		if ctxErr := m.ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		// Without git's answer a copy still leaves out every ignored
+		// file, as it would for a source that is not a repository.
+		files = nil
+	}
+	m.tracked = make(map[string]bool, len(files))
+	for _, file := range files {
+		for entry := file; entry != "." && entry != "/" && !m.tracked[entry]; entry = path.Dir(entry) {
+			m.tracked[entry] = true
+		}
+	}
+	return nil
+}
+
+func parseIgnorePattern(base, line string) (ignorePattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignorePattern{}, false
+	}
+
+	pattern := ignorePattern{base: base}
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, "\\")
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		pattern.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignorePattern{}, false
+	}
+	pattern.glob = line
+	return pattern, true
+}
+
+// matchIgnoreGlob matches slash-separated names against a pattern where
+// "**" spans any number of path segments.
+func matchIgnoreGlob(pattern, name string) bool {
+	return matchIgnoreSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchIgnoreSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchIgnoreSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// runGitTrackedFiles lists tracked files relative to source. Sources that are
+// not inside a git repository have no tracked files; inside one, a git that
+// is missing or fails is an error, since nobody can tell what it tracks.
+func runGitTrackedFiles(ctx context.Context, source string) ([]string, error) {
+	// Only stdout holds the file list; warnings on stderr must not end up
+	// in it.
+	output, err := exec.CommandContext(ctx, "git", "-C", source, "ls-files", "-z", "--cached").Output()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !inGitRepo(source) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list files git tracks in %s: %w", source, err)
+	}
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// inGitRepo reports whether dir or one of its parents holds a .git entry,
+// which is how git itself finds a repository.
+func inGitRepo(dir string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return true
+	}
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,152 @@
+package hatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchIgnoreGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "*.log", name: "debug.log", want: true},
+		{pattern: "build", name: "build", want: true},
+		{pattern: "docs/*.md", name: "docs/readme.md", want: true},
+		{pattern: "docs/*.md", name: "docs/api/readme.md", want: false},
+		{pattern: "**/cache", name: "a/b/cache", want: true},
+		{pattern: "**/cache", name: "cache", want: true},
+		{pattern: "src/**/gen", name: "src/x/y/gen", want: true},
+		{pattern: "src/**", name: "src/x", want: true},
+		{pattern: "src/**", name: "lib/x", want: false},
+	}
+
+	for _, tc := range tests {
+		if got := matchIgnoreGlob(tc.pattern, tc.name); got != tc.want {
+			t.Fatalf("matchIgnoreGlob(%q, %q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCopyDirHonorsIgnoreRules(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{
+		".gitignore":                "node_modules/\n*.log\n!keep.log\n/target\n",
+		".hatchignore":              ".venv\n",
+		"index.js":                  "code",
+		"debug.log":                 "noise",
+		"keep.log":                  "signal",
+		"node_modules/dep/index.js": "dep",
+		"target/app":                "binary",
+		"nested/target/notes.txt":   "anchored pattern only applies at the root",
+		"nested/.gitignore":         "dist\n",
+		"nested/dist/bundle.js":     "bundle",
+		"nested/src.js":             "src",
+		".venv/bin/python":          "python",
+		"coverage/lcov.info":        "coverage",
+	})
+
+	target := filepath.Join(t.TempDir(), "target")
+	opts := copyOptions{ignore: ignoreRules{files: []string{".gitignore"}, exclude: []string{"coverage/"}}}
+	if _, err := copyDir(context.Background(), source, target, opts); err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+
+	for _, kept := range []string{".gitignore", ".hatchignore", "index.js", "keep.log", "nested/target/notes.txt", "nested/src.js"} {
+		if _, err := os.Stat(filepath.Join(target, kept)); err != nil {
+			t.Fatalf("expected %s to be copied: %v", kept, err)
+		}
+	}
+	for _, skipped := range []string{"debug.log", "node_modules", "target", "nested/dist", ".venv", "coverage"} {
+		if _, err := os.Stat(filepath.Join(target, skipped)); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be skipped, err=%v", skipped, err)
+		}
+	}
+}
+
+func TestCopyDirExcludeOverridesIgnoreFileNegation(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{
+		".gitignore": "*.log\n!keep.log\n",
+		"keep.log":   "negated in .gitignore",
+		"main.go":    "code",
+	})
+
+	target := filepath.Join(t.TempDir(), "target")
+	opts := copyOptions{ignore: ignoreRules{files: []string{".gitignore"}, exclude: []string{"*.log"}}}
+	if _, err := copyDir(context.Background(), source, target, opts); err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "keep.log")); !os.IsNotExist(err) {
+		t.Fatalf("expected --exclude to win over !keep.log, err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "main.go")); err != nil {
+		t.Fatalf("expected main.go to be copied: %v", err)
+	}
+}
+
+func TestCopyDirCopiesTrackedIgnoredFiles(t *testing.T) {
+	requireGit(t)
+	source := filepath.Join(t.TempDir(), "repo")
+	writeTree(t, source, map[string]string{
+		".gitignore":     "build/\n",
+		"build/keep.txt": "tracked despite the ignore rule",
+		"build/junk.o":   "untracked artifact",
+	})
+	runGit(t, "", "init", "--quiet", source)
+	runGit(t, source, "add", ".gitignore")
+	runGit(t, source, "add", "-f", "build/keep.txt")
+
+	target := filepath.Join(t.TempDir(), "target")
+	opts := copyOptions{ignore: ignoreRules{files: []string{".gitignore"}}}
+	if _, err := copyDir(context.Background(), source, target, opts); err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(target, "build", "keep.txt")); err != nil {
+		t.Fatalf("expected tracked file to be copied: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "build", "junk.o")); !os.IsNotExist(err) {
+		t.Fatalf("expected untracked ignored file to be skipped, err=%v", err)
+	}
+}
+
+func TestCopyDirNoIgnoreCopiesEverything(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{
+		".hatchignore":   "dist\n",
+		"dist/bundle.js": "bundle",
+	})
+
+	target := filepath.Join(t.TempDir(), "target")
+	if _, err := copyDir(context.Background(), source, target, copyOptions{ignore: ignoreRules{disabled: true}}); err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "dist", "bundle.js")); err != nil {
+		t.Fatalf("expected --no-ignore to copy ignored files: %v", err)
+	}
+}
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create parent for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errHatcheryBusy is returned when another hatch process holds the hatchery
+// lock for longer than hatcheryLockTimeout.
+var errHatcheryBusy = errors.New("hatchery is busy: another hatch process is changing it")
+
+// hatcheryLockTimeout bounds how long a mutation waits for another process.
+// The lock only covers claiming, renaming, and removing paths, never long
+// copies or clones, so waits are normally short.
+var hatcheryLockTimeout = 10 * time.Second
+
+const lockPollInterval = 20 * time.Millisecond
+
+func lockPath(root string) string {
+	return filepath.Join(root, stateDirName, "lock")
+}
+
+// withHatcheryLock runs fn while holding an advisory lock on the hatchery
+// root, so concurrent hatch processes cannot both claim or move the same
+// project path.
+func withHatcheryLock(root string, fn func() error) error {
+	unlock, err := lockHatchery(root)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+func lockHatchery(root string) (func(), error) {
+	if err := os.MkdirAll(filepath.Join(root, stateDirName), 0o755); err != nil {
+		return nil, fmt.Errorf("create state directory: %w", err)
+	}
+	file, err := os.OpenFile(lockPath(root), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open hatchery lock: %w", err)
+	}
+
+	deadline := time.Now().Add(hatcheryLockTimeout)
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("lock hatchery: %w", err)
+		}
+		if locked {
+			return func() {
+				_ = unlockFile(file)
+				file.Close()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("%w (waited %s for %s)", errHatcheryBusy, hatcheryLockTimeout, lockPath(root))
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !windows
+
+package hatch
+
+import "os"
+
+// Platforms without a supported lock primitive run unlocked.
+func tryLockFile(*os.File) (bool, error) {
+	return true, nil
+}
+
+func unlockFile(*os.File) error {
+	return nil
+}
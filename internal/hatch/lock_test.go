@@ -0,0 +1,147 @@
+package hatch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentCreatesClaimEachNameOnce(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{"main.go": "package main", "pkg/lib.go": "package pkg"})
+
+	tests := []struct {
+		name   string
+		create func(root string) (string, error)
+	}{
+		{name: "create", create: func(root string) (string, error) {
+			return createProject(root, "race", fixedNow(), existsFail)
+		}},
+		{name: "copy", create: func(root string) (string, error) {
+			path, _, err := copyProject(context.Background(), root, source, "race", fixedNow(), existsFail, copyOptions{})
+			return path, err
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := filepath.Join(t.TempDir(), "hatchery")
+			const workers = 8
+
+			var wg sync.WaitGroup
+			errs := make(chan error, workers)
+			start := make(chan struct{})
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					_, err := tc.create(root)
+					errs <- err
+				}()
+			}
+			close(start)
+			wg.Wait()
+			close(errs)
+
+			created := 0
+			for err := range errs {
+				switch {
+				case err == nil:
+					created++
+				case !strings.Contains(err.Error(), "project already exists"):
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if created != 1 {
+				t.Fatalf("%d concurrent %s calls succeeded, want exactly 1", created, tc.name)
+			}
+			assertStagingEmpty(t, root)
+		})
+	}
+}
+
+func TestHatcheryLockTimesOutWhenBusy(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	unlock, err := lockHatchery(root)
+	if err != nil {
+		t.Fatalf("lockHatchery returned error: %v", err)
+	}
+	defer unlock()
+
+	original := hatcheryLockTimeout
+	hatcheryLockTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { hatcheryLockTimeout = original })
+
+	_, err = createProject(root, "blocked", fixedNow(), existsFail)
+	if !errors.Is(err, errHatcheryBusy) {
+		t.Fatalf("createProject error = %v, want errHatcheryBusy", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2026-02-28-blocked")); !os.IsNotExist(err) {
+		t.Fatalf("expected no project while the hatchery is busy, err=%v", err)
+	}
+	assertStagingEmpty(t, root)
+}
+
+// TestHatcheryLockAcrossProcesses holds the lock from a child process and
+// checks that this process waits for it.
+func TestHatcheryLockAcrossProcesses(t *testing.T) {
+	if root := os.Getenv("HATCH_TEST_LOCK_HOLDER"); root != "" {
+		unlock, err := lockHatchery(root)
+		if err != nil {
+			fmt.Println("error:", err)
+			os.Exit(1)
+		}
+		fmt.Println("locked")
+		_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+		unlock()
+		os.Exit(0)
+	}
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHatcheryLockAcrossProcesses$")
+	cmd.Env = append(os.Environ(), "HATCH_TEST_LOCK_HOLDER="+root)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatalf("child stdin: %v", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("child stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start child: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _ = cmd.Wait() })
+	if line, err := bufio.NewReader(stdout).ReadString('\n'); err != nil || strings.TrimSpace(line) != "locked" {
+		t.Fatalf("child did not take the lock: %q, %v", line, err)
+	}
+
+	original := hatcheryLockTimeout
+	hatcheryLockTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { hatcheryLockTimeout = original })
+	if _, err := createProject(root, "contended", fixedNow(), existsFail); !errors.Is(err, errHatcheryBusy) {
+		t.Fatalf("createProject error = %v, want errHatcheryBusy", err)
+	}
+
+	hatcheryLockTimeout = 5 * time.Second
+	done := make(chan error, 1)
+	go func() {
+		_, err := createProject(root, "contended", fixedNow(), existsFail)
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if _, err := stdin.Write([]byte("\n")); err != nil {
+		t.Fatalf("release child lock: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("createProject after release returned error: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package hatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+)
+
+// metadata is per-hatchery state kept in <root>/.hatch/metadata.json. Users
+// may edit it by hand. hatch ignores fields it does not know and drops them
+// when it rewrites the file.
+type metadata struct {
+	// Projects is keyed by project directory name.
+	Projects map[string]projectMeta `json:"projects,omitempty"`
+	// Sort is the browser order last chosen with Ctrl+S.
+	Sort sortMode `json:"sort,omitempty"`
+}
+
+type projectMeta struct {
+	// Tags are short labels such as "auth", shown as #auth in the browser.
+	Tags []string `json:"tags,omitempty"`
+	// Pinned projects are listed above the rest in the browser and are never
+	// removed by cleanup.
+	Pinned bool `json:"pinned,omitempty"`
+	// Note says why the project exists.
+	Note string `json:"note,omitempty"`
+	// Visits and LastVisit count entries into the project for the frecency
+	// order.
+	Visits    int       `json:"visits,omitempty"`
+	LastVisit time.Time `json:"last_visit,omitzero"`
+	// PromotedTo is where hatch promote moved the project, and PromotedAt
+	// when.
+	PromotedTo string    `json:"promoted_to,omitempty"`
+	PromotedAt time.Time `json:"promoted_at,omitzero"`
+	// AdoptedFrom is the directory hatch adopt brought in, and AdoptedAt
+	// when.
+	AdoptedFrom string    `json:"adopted_from,omitempty"`
+	AdoptedAt   time.Time `json:"adopted_at,omitzero"`
+	// Editor overrides the configured editor command for this project.
+	Editor string `json:"editor,omitempty"`
+	// Env is applied on entry when env activation is enabled. Unlike a
+	// project's own .hatch/env file it needs no hatch allow.
+	Env *projectEnv `json:"env,omitempty"`
+	// EnvAllowed is the SHA-256 of the project's .hatch/env file as last
+	// approved with hatch allow.
+	EnvAllowed string `json:"env_allowed,omitempty"`
+}
+
+func metadataPath(root string) string {
+	return filepath.Join(root, stateDirName, "metadata.json")
+}
+
+func loadMetadata(root string) (metadata, error) {
+	var md metadata
+	data, err := os.ReadFile(metadataPath(root))
+	if errors.Is(err, os.ErrNotExist) {
+		return md, nil
+	}
+	if err != nil {
+		return md, fmt.Errorf("read metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &md); err != nil {
+		return md, fmt.Errorf("parse metadata %s: %w", metadataPath(root), err)
+	}
+	return md, nil
+}
+
+// project returns the metadata for the project at path, which may be empty.
+func (md metadata) project(path string) projectMeta {
+	return md.Projects[filepath.Base(path)]
+}
+
+// setProject stores meta for the project at path, dropping the entry once
+// it is empty.
+func (md *metadata) setProject(path string, meta projectMeta) {
+	name := filepath.Base(path)
+	if reflect.ValueOf(meta).IsZero() {
+		delete(md.Projects, name)
+		return
+	}
+	if md.Projects == nil {
+		md.Projects = make(map[string]projectMeta)
+	}
+	md.Projects[name] = meta
+}
+
+// moveProject carries the metadata of the project at from over to to after
+// a rename.
+func (md *metadata) moveProject(from, to string) {
+	meta := md.project(from)
+	md.setProject(from, projectMeta{})
+	md.setProject(to, meta)
+}
+
+// updateMetadata loads the metadata, applies change, and writes the result
+// back atomically, all under the hatchery lock so concurrent runs do not
+// lose each other's edits. Callers must not already hold the lock.
+func updateMetadata(root string, change func(md *metadata) error) error {
+	return withHatcheryLock(root, func() error {
+		md, err := loadMetadata(root)
+		if err != nil {
+			return err
+		}
+		if err := change(&md); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(md, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode metadata: %w", err)
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(metadataPath(root)), "metadata-*.json")
+		if err != nil {
+			return fmt.Errorf("write metadata: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			tmp.Close()
+			return fmt.Errorf("write metadata: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return fmt.Errorf("write metadata: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), metadataPath(root)); err != nil {
+			return fmt.Errorf("write metadata: %w", err)
+		}
+		return nil
+	})
+}
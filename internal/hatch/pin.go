@@ -0,0 +1,42 @@
+package hatch
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// setProjectPinned pins or unpins the project at path.
+func setProjectPinned(root, path string, pinned bool) error {
+	return updateMetadata(root, func(md *metadata) error {
+		meta := md.project(path)
+		meta.Pinned = pinned
+		md.setProject(path, meta)
+		return nil
+	})
+}
+
+// runPin keeps a project at the top of the browser and out of reach of
+// cleanup.
+func runPin(c commandContext, args []string) error {
+	project, err := projectArg(c, "pin", args)
+	if err != nil {
+		return err
+	}
+	if err := setProjectPinned(filepath.Dir(project.Path), project.Path, true); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render("Pinned "+project.Name))
+	return nil
+}
+
+func runUnpin(c commandContext, args []string) error {
+	project, err := projectArg(c, "unpin", args)
+	if err != nil {
+		return err
+	}
+	if err := setProjectPinned(filepath.Dir(project.Path), project.Path, false); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render("Unpinned "+project.Name))
+	return nil
+}
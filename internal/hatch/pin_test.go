@@ -0,0 +1,33 @@
+package hatch
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPinAndUnpin(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	project := filepath.Join(root, "2026-01-02-experiment")
+	writeTree(t, project, map[string]string{"keep": ""})
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"pin", "experiment"}, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("pin returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Pinned 2026-01-02-experiment") {
+		t.Fatalf("pin output = %q", out.String())
+	}
+	if md, _ := loadMetadata(root); !md.project(project).Pinned {
+		t.Fatal("expected project to be pinned")
+	}
+
+	if err := run([]string{"unpin", "2026-01-02-experiment"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("unpin returned error: %v", err)
+	}
+	if md, _ := loadMetadata(root); len(md.Projects) != 0 {
+		t.Fatalf("expected unpinning to drop the empty entry, got %+v", md.Projects)
+	}
+}
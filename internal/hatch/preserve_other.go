@@ -0,0 +1,24 @@
+//go:build !linux && !darwin
+
+package hatch
+
+import (
+	"io/fs"
+	"time"
+)
+
+func readFileMeta(_ string, info fs.FileInfo) (fileMeta, error) {
+	return fileMeta{atime: info.ModTime()}, nil
+}
+
+func setLinkTimes(string, time.Time, time.Time) error {
+	return nil
+}
+
+func copyXattrs(string, string) ([]string, error) {
+	return nil, nil
+}
+
+func makeSpecialFile(string, fs.FileMode, uint64) error {
+	return errSpecialFileUnsupported
+}
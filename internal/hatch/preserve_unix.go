@@ -0,0 +1,113 @@
+//go:build linux || darwin
+
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func readFileMeta(path string, info fs.FileInfo) (fileMeta, error) {
+	var st unix.Stat_t
+	if err := unix.Lstat(path, &st); err != nil {
+		return fileMeta{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+	return fileMeta{
+		atime: time.Unix(int64(st.Atim.Sec), int64(st.Atim.Nsec)),
+		id:    fileID{dev: uint64(st.Dev), ino: uint64(st.Ino)},
+		nlink: uint64(st.Nlink),
+		rdev:  uint64(st.Rdev),
+	}, nil
+}
+
+// setLinkTimes sets the times of a symlink itself rather than its target.
+func setLinkTimes(path string, atime, mtime time.Time) error {
+	ts := []unix.Timespec{unix.NsecToTimespec(atime.UnixNano()), unix.NsecToTimespec(mtime.UnixNano())}
+	if err := unix.UtimesNanoAt(unix.AT_FDCWD, path, ts, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return fmt.Errorf("set times on %s: %w", path, err)
+	}
+	return nil
+}
+
+// copyXattrs copies extended attributes from source to target and returns
+// the names the target filesystem or the current user could not set.
+func copyXattrs(source, target string) ([]string, error) {
+	names, err := listXattrs(source)
+	if err != nil {
+		if xattrUnsupported(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list xattrs of %s: %w", source, err)
+	}
+
+	var rejected []string
+	for _, name := range names {
+		value, err := getXattr(source, name)
+		if err != nil {
+			return rejected, fmt.Errorf("read xattr %s of %s: %w", name, source, err)
+		}
+		if err := unix.Lsetxattr(target, name, value, 0); err != nil {
+			if xattrUnsupported(err) {
+				rejected = append(rejected, fmt.Sprintf("%s (%v)", name, err))
+				continue
+			}
+			return rejected, fmt.Errorf("set xattr %s on %s: %w", name, target, err)
+		}
+	}
+	return rejected, nil
+}
+
+func listXattrs(path string) ([]string, error) {
+	size, err := unix.Llistxattr(path, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	size, err = unix.Llistxattr(path, buf)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range strings.Split(string(buf[:size]), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	size, err := unix.Lgetxattr(path, name, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	size, err = unix.Lgetxattr(path, name, buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:size], nil
+}
+
+func xattrUnsupported(err error) bool {
+	return errors.Is(err, unix.ENOTSUP) || errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.EACCES)
+}
+
+// makeSpecialFile recreates a named pipe or device node at target.
+func makeSpecialFile(target string, mode fs.FileMode, rdev uint64) error {
+	perm := uint32(mode.Perm())
+	switch {
+	case mode&fs.ModeNamedPipe != 0:
+		return unix.Mkfifo(target, perm)
+	case mode&fs.ModeCharDevice != 0:
+		return unix.Mknod(target, unix.S_IFCHR|perm, int(rdev))
+	case mode&fs.ModeDevice != 0:
+		return unix.Mknod(target, unix.S_IFBLK|perm, int(rdev))
+	}
+	return errSpecialFileUnsupported
+}
@@ -0,0 +1,146 @@
+//go:build linux || darwin
+
+package hatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestCopyDirPreservesMetadata(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{
+		"Makefile":      "all:",
+		"build/out.o":   "object",
+		"locked/a.txt":  "a",
+		"shared/one.md": "same inode",
+	})
+	if err := os.Link(filepath.Join(source, "shared", "one.md"), filepath.Join(source, "shared", "two.md")); err != nil {
+		t.Fatalf("create hardlink: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(source, "empty"), 0o711); err != nil {
+		t.Fatalf("create empty dir: %v", err)
+	}
+	if err := unix.Mkfifo(filepath.Join(source, "pipe"), 0o600); err != nil {
+		t.Fatalf("create fifo: %v", err)
+	}
+	if err := os.Symlink("Makefile", filepath.Join(source, "link")); err != nil {
+		t.Fatalf("create symlink: %v", err)
+	}
+	if err := os.Chmod(filepath.Join(source, "build", "out.o"), 0o751); err != nil {
+		t.Fatalf("chmod file: %v", err)
+	}
+	if err := os.Chmod(filepath.Join(source, "empty"), 0o711); err != nil {
+		t.Fatalf("chmod empty dir: %v", err)
+	}
+	xattrs := unix.Setxattr(filepath.Join(source, "Makefile"), "user.hatch", []byte("kept"), 0) == nil
+
+	atime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mtime := time.Date(2021, 6, 7, 8, 9, 10, 0, time.UTC)
+	for _, name := range []string{"Makefile", "build/out.o", "build", "pipe", "empty", "locked/a.txt"} {
+		if err := os.Chtimes(filepath.Join(source, name), atime, mtime); err != nil {
+			t.Fatalf("set times on %s: %v", name, err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(source, "locked"), 0o555); err != nil {
+		t.Fatalf("chmod locked dir: %v", err)
+	}
+	if err := os.Chtimes(filepath.Join(source, "locked"), atime, mtime); err != nil {
+		t.Fatalf("set times on locked: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(source, "locked"), 0o755) })
+
+	target := filepath.Join(t.TempDir(), "target")
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(target, "locked"), 0o755) })
+	result, err := copyDir(context.Background(), source, target, copyOptions{preserve: true})
+	if err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+	if len(result.Skipped) != 0 {
+		t.Fatalf("unexpected skipped entries: %v", result.Skipped)
+	}
+
+	for _, name := range []string{"Makefile", "build/out.o", "build", "pipe", "empty", "locked", "locked/a.txt"} {
+		info, err := os.Lstat(filepath.Join(target, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("stat copied %s: %v", name, err)
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Fatalf("%s mtime = %v, want %v", name, info.ModTime(), mtime)
+		}
+		meta, err := readFileMeta(filepath.Join(target, filepath.FromSlash(name)), info)
+		if err != nil {
+			t.Fatalf("read meta of %s: %v", name, err)
+		}
+		if !meta.atime.Equal(atime) {
+			t.Fatalf("%s atime = %v, want %v", name, meta.atime, atime)
+		}
+	}
+
+	wantModes := map[string]os.FileMode{"build/out.o": 0o751, "empty": os.ModeDir | 0o711, "locked": os.ModeDir | 0o555, "pipe": os.ModeNamedPipe | 0o600}
+	for name, want := range wantModes {
+		info, err := os.Lstat(filepath.Join(target, filepath.FromSlash(name)))
+		if err != nil || info.Mode() != want {
+			t.Fatalf("%s mode = %v, %v; want %v", name, info.Mode(), err, want)
+		}
+	}
+
+	one, err := os.Stat(filepath.Join(target, "shared", "one.md"))
+	if err != nil {
+		t.Fatalf("stat one.md: %v", err)
+	}
+	two, err := os.Stat(filepath.Join(target, "shared", "two.md"))
+	if err != nil {
+		t.Fatalf("stat two.md: %v", err)
+	}
+	if !os.SameFile(one, two) {
+		t.Fatalf("expected hardlinked files to stay linked in the copy")
+	}
+	original, err := os.Stat(filepath.Join(source, "shared", "one.md"))
+	if err != nil {
+		t.Fatalf("stat source one.md: %v", err)
+	}
+	if os.SameFile(one, original) {
+		t.Fatalf("expected the copy not to link back into the source")
+	}
+
+	if xattrs {
+		value, err := getXattr(filepath.Join(target, "Makefile"), "user.hatch")
+		if err != nil || string(value) != "kept" {
+			t.Fatalf("xattr user.hatch = %q, %v", value, err)
+		}
+	}
+}
+
+func TestCopyDirReportsSpecialFiles(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{"main.go": "package main"})
+	if err := unix.Mkfifo(filepath.Join(source, "pipe"), 0o600); err != nil {
+		t.Fatalf("create fifo: %v", err)
+	}
+
+	target := filepath.Join(t.TempDir(), "target")
+	result, err := copyDir(context.Background(), source, target, copyOptions{})
+	if err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Path != "pipe" || !strings.Contains(result.Skipped[0].Reason, "named pipe") {
+		t.Fatalf("skipped = %v, want the named pipe", result.Skipped)
+	}
+	if _, err := os.Lstat(filepath.Join(target, "pipe")); !os.IsNotExist(err) {
+		t.Fatalf("expected pipe not to be copied, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "main.go")); err != nil {
+		t.Fatalf("expected regular file to be copied: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package hatch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const progressInterval = 100 * time.Millisecond
+
+func (p copyProgress) String() string {
+	return fmt.Sprintf("%d/%d files, %s/%s", p.Files, p.TotalFiles, formatBytes(p.Bytes), formatBytes(p.TotalBytes))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// terminalProgress rewrites a single stderr line with the latest copy
+// progress, throttled so large trees do not flood the terminal.
+type terminalProgress struct {
+	out   io.Writer
+	now   func() time.Time
+	last  time.Time
+	width int
+}
+
+func newTerminalProgress(out io.Writer, now func() time.Time) *terminalProgress {
+	return &terminalProgress{out: out, now: now}
+}
+
+func (p *terminalProgress) copy(progress copyProgress) {
+	done := progress.Files >= progress.TotalFiles
+	current := p.now()
+	if !done && current.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = current
+	p.print("Copying " + progress.String())
+}
+
+func (p *terminalProgress) print(line string) {
+	padding := ""
+	if len(line) < p.width {
+		padding = strings.Repeat(" ", p.width-len(line))
+	}
+	p.width = len(line)
+	fmt.Fprint(p.out, "\r"+line+padding)
+}
+
+// finish ends the progress line so later output starts on a fresh line.
+func (p *terminalProgress) finish() {
+	if p.width > 0 {
+		fmt.Fprintln(p.out)
+		p.width = 0
+	}
+}
+
+// progressLineWriter splits streamed output on carriage returns and newlines,
+// as git uses both for its progress meter, and reports each complete line.
+type progressLineWriter struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	report func(string)
+}
+
+func (w *progressLineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	for {
+		data := w.buf.Bytes()
+		idx := bytes.IndexAny(data, "\r\n")
+		if idx < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(data[:idx]))
+		w.buf.Next(idx + 1)
+		if line != "" {
+			w.report(line)
+		}
+	}
+	return len(p), nil
+}
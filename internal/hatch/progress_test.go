@@ -0,0 +1,106 @@
+package hatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCopyDirReportsProgress(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	if err := os.MkdirAll(filepath.Join(source, "nested"), 0o755); err != nil {
+		t.Fatalf("create source dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "nested", "b.txt"), []byte("world!"), 0o644); err != nil {
+		t.Fatalf("write b.txt: %v", err)
+	}
+
+	var updates []copyProgress
+	opts := copyOptions{progress: func(progress copyProgress) {
+		updates = append(updates, progress)
+	}}
+	if _, err := copyDir(context.Background(), source, filepath.Join(t.TempDir(), "target"), opts); err != nil {
+		t.Fatalf("copyDir returned error: %v", err)
+	}
+
+	if len(updates) != 3 {
+		t.Fatalf("expected an initial update plus one per file, got %#v", updates)
+	}
+	want := copyProgress{Files: 2, TotalFiles: 2, Bytes: 11, TotalBytes: 11}
+	if got := updates[len(updates)-1]; got != want {
+		t.Fatalf("final progress = %#v, want %#v", got, want)
+	}
+}
+
+func TestCopyDirStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	source := filepath.Join(t.TempDir(), "source")
+	if err := os.MkdirAll(source, 0o755); err != nil {
+		t.Fatalf("create source dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "a.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatalf("write a.txt: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := copyDir(ctx, source, filepath.Join(t.TempDir(), "target"), copyOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestProgressLineWriterSplitsCarriageReturns(t *testing.T) {
+	t.Parallel()
+
+	var lines []string
+	w := &progressLineWriter{report: func(line string) {
+		lines = append(lines, line)
+	}}
+	w.Write([]byte("Cloning into 'x'...\nReceiving objects:  50% (1/2)\rReceiving"))
+	w.Write([]byte(" objects: 100% (2/2), done.\n"))
+
+	want := []string{"Cloning into 'x'...", "Receiving objects:  50% (1/2)", "Receiving objects: 100% (2/2), done."}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("lines = %#v, want %#v", lines, want)
+	}
+}
+
+func TestTerminalProgressRewritesLine(t *testing.T) {
+	t.Parallel()
+
+	out := new(bytes.Buffer)
+	progress := newTerminalProgress(out, fixedNow)
+	progress.copy(copyProgress{Files: 0, TotalFiles: 2, Bytes: 0, TotalBytes: 2048})
+	progress.copy(copyProgress{Files: 1, TotalFiles: 2, Bytes: 1024, TotalBytes: 2048})
+	progress.copy(copyProgress{Files: 2, TotalFiles: 2, Bytes: 2048, TotalBytes: 2048})
+	progress.finish()
+
+	got := out.String()
+	if strings.Contains(got, "1/2 files") {
+		t.Fatalf("expected intermediate update to be throttled, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\rCopying 2/2 files, 2.0 KiB/2.0 KiB\n") {
+		t.Fatalf("unexpected progress output %q", got)
+	}
+}
+
+func TestGitErrorMessageDropsProgress(t *testing.T) {
+	t.Parallel()
+
+	output := []byte("Cloning into 'x'...\nremote: Counting objects: 10% (1/10)\rremote: Counting objects: 100% (10/10)\nfatal: early EOF\n")
+	if got := gitErrorMessage(output); got != "fatal: early EOF" {
+		t.Fatalf("gitErrorMessage = %q", got)
+	}
+}
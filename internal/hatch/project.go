@@ -1,10 +1,11 @@
 package hatch
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"net/url"
 	"os"
 	"os/exec"
@@ -16,9 +17,10 @@ import (
 )
 
 var (
-	errInvalidName   = errors.New("project name must contain at least one valid character")
-	errInvalidGitURL = errors.New("git URL must use ssh or https")
-	errNotGitRepo    = errors.New("path is not a git repository")
+	errInvalidName     = errors.New("project name must contain at least one valid character")
+	errInvalidGitURL   = errors.New("git URL must use ssh or https")
+	errNotGitRepo      = errors.New("path is not a git repository")
+	errProjectNotFound = errors.New("no such project")
 )
 
 var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
@@ -29,9 +31,57 @@ var gitRepoRootFn = resolveGitRepoRoot
 var gitBranchExistsFn = runGitBranchExists
 var gitWorktreeAddFn = runGitWorktreeAdd
 
+// existsPolicy decides what creating a project does when its dated
+// directory is already taken.
+type existsPolicy string
+
+const (
+	existsFail existsPolicy = "fail"
+	// existsSuffix creates <dir>-2, <dir>-3, ... instead.
+	existsSuffix existsPolicy = "suffix"
+	// existsOpen returns a *projectExistsError like existsFail; callers
+	// then open the existing project.
+	existsOpen existsPolicy = "open"
+)
+
+var existsPolicies = []existsPolicy{existsFail, existsSuffix, existsOpen}
+
+func parseExistsPolicy(value string) (existsPolicy, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return existsFail, nil
+	}
+	for _, policy := range existsPolicies {
+		if string(policy) == value {
+			return policy, nil
+		}
+	}
+	return "", fmt.Errorf("unknown on-exists policy %q (use fail, suffix, or open)", value)
+}
+
+// projectExistsError reports a creation whose target directory is taken.
+type projectExistsError struct {
+	Path string
+}
+
+func (e *projectExistsError) Error() string {
+	return "project already exists: " + e.Path
+}
+
 type Project struct {
 	Name string
 	Path string
+	// Target is the directory a symlinked project points at, and empty for
+	// a project that lives in the hatchery.
+	Target string
+}
+
+// dir is where the project's files are: its target when it is symlinked.
+func (p Project) dir() string {
+	if p.Target != "" {
+		return p.Target
+	}
+	return p.Path
 }
 
 func hatcheryRoot() (string, error) {
@@ -90,68 +140,70 @@ func projectDirName(name string, now time.Time) (string, error) {
 	return fmt.Sprintf("%s-%s", now.Format("2006-01-02"), norm), nil
 }
 
-func createProject(root, name string, now time.Time) (string, error) {
+func createProject(root, name string, now time.Time, onExists existsPolicy) (string, error) {
 	dirName, err := projectDirName(name, now)
 	if err != nil {
 		return "", err
 	}
+	return createProjectDir(root, dirName, onExists)
+}
 
+// createProjectDir creates the empty project directory dirName, which
+// already carries its date.
+func createProjectDir(root, dirName string, onExists existsPolicy) (string, error) {
 	if err := os.MkdirAll(root, 0o755); err != nil {
 		return "", fmt.Errorf("create hatchery root: %w", err)
 	}
 
-	target := filepath.Join(root, dirName)
-	if _, err := os.Stat(target); err == nil {
-		return "", fmt.Errorf("project already exists: %s", target)
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return "", fmt.Errorf("check project directory: %w", err)
-	}
-
-	if err := os.MkdirAll(target, 0o755); err != nil {
-		return "", fmt.Errorf("create project directory: %w", err)
+	target, err := stageProject(root, filepath.Join(root, dirName), onExists, func(staged string) error {
+		if err := os.Mkdir(staged, 0o755); err != nil {
+			return fmt.Errorf("create project directory: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return target, nil
 }
 
-func copyProject(root, source, name string, now time.Time) (string, error) {
+func copyProject(ctx context.Context, root, source, name string, now time.Time, onExists existsPolicy, opts copyOptions) (string, copyResult, error) {
 	dirName, err := projectDirName(name, now)
 	if err != nil {
-		return "", err
+		return "", copyResult{}, err
 	}
 
 	resolvedSource, err := expandPath(source)
 	if err != nil {
-		return "", err
+		return "", copyResult{}, err
 	}
 
 	srcInfo, err := os.Stat(resolvedSource)
 	if err != nil {
-		return "", fmt.Errorf("read source directory: %w", err)
+		return "", copyResult{}, fmt.Errorf("read source directory: %w", err)
 	}
 	if !srcInfo.IsDir() {
-		return "", fmt.Errorf("source must be a directory: %s", resolvedSource)
+		return "", copyResult{}, fmt.Errorf("source must be a directory: %s", resolvedSource)
 	}
 
 	if err := os.MkdirAll(root, 0o755); err != nil {
-		return "", fmt.Errorf("create hatchery root: %w", err)
+		return "", copyResult{}, fmt.Errorf("create hatchery root: %w", err)
 	}
 
-	target := filepath.Join(root, dirName)
-	if _, err := os.Stat(target); err == nil {
-		return "", fmt.Errorf("project already exists: %s", target)
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return "", fmt.Errorf("check project directory: %w", err)
-	}
-
-	if err := copyDir(resolvedSource, target); err != nil {
-		return "", err
+	var result copyResult
+	target, err := stageProject(root, filepath.Join(root, dirName), onExists, func(staged string) error {
+		result, err = copyDir(ctx, resolvedSource, staged, opts)
+		return err
+	})
+	if err != nil {
+		return "", result, err
 	}
 
-	return target, nil
+	return target, result, nil
 }
 
-func worktreeProject(root, source, name string, now time.Time) (string, error) {
+func worktreeProject(ctx context.Context, root, source, name string, now time.Time, onExists existsPolicy, progress io.Writer) (string, error) {
 	dirName, err := projectDirName(name, now)
 	if err != nil {
 		return "", err
@@ -179,26 +231,21 @@ func worktreeProject(root, source, name string, now time.Time) (string, error) {
 		return "", fmt.Errorf("create hatchery root: %w", err)
 	}
 
-	target := filepath.Join(root, dirName)
-	if _, err := os.Stat(target); err == nil {
-		return "", fmt.Errorf("project already exists: %s", target)
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return "", fmt.Errorf("check project directory: %w", err)
+	target, err := claimProjectDir(root, filepath.Join(root, dirName), onExists)
+	if err != nil {
+		return "", err
 	}
 
-	branchName, err := nextAvailableBranchName(repoRoot, dirName)
+	branchName, err := nextAvailableBranchName(repoRoot, filepath.Base(target))
 	if err != nil {
+		_ = os.RemoveAll(target)
 		return "", err
 	}
 
-	output, err := gitWorktreeAddFn(repoRoot, target, branchName)
+	output, err := gitWorktreeAddFn(ctx, repoRoot, target, branchName, progress)
 	if err != nil {
 		_ = os.RemoveAll(target)
-		msg := strings.TrimSpace(string(output))
-		if msg != "" {
-			return "", fmt.Errorf("create git worktree: %s", msg)
-		}
-		return "", fmt.Errorf("create git worktree: %w", err)
+		return "", gitCommandError("create git worktree", output, err)
 	}
 
 	return target, nil
@@ -280,7 +327,7 @@ func repoNameFromGitURL(raw string) (string, error) {
 	return normalized, nil
 }
 
-func cloneProject(root, repoURL string, now time.Time) (string, error) {
+func cloneProject(ctx context.Context, root, repoURL string, now time.Time, onExists existsPolicy, progress io.Writer) (string, error) {
 	repoName, err := repoNameFromGitURL(repoURL)
 	if err != nil {
 		return "", err
@@ -295,29 +342,77 @@ func cloneProject(root, repoURL string, now time.Time) (string, error) {
 		return "", fmt.Errorf("create hatchery root: %w", err)
 	}
 
-	target := filepath.Join(root, dirName)
-	if _, err := os.Stat(target); err == nil {
-		return "", fmt.Errorf("project already exists: %s", target)
-	} else if !errors.Is(err, os.ErrNotExist) {
-		return "", fmt.Errorf("check project directory: %w", err)
-	}
-
-	output, err := gitCloneFn(repoURL, target)
-	if err != nil {
-		_ = os.RemoveAll(target)
-		msg := strings.TrimSpace(string(output))
-		if msg != "" {
-			return "", fmt.Errorf("clone repository: %s", msg)
+	target, err := stageProject(root, filepath.Join(root, dirName), onExists, func(staged string) error {
+		output, err := gitCloneFn(ctx, repoURL, staged, progress)
+		if err != nil {
+			return gitCommandError("clone repository", output, err)
 		}
-		return "", fmt.Errorf("clone repository: %w", err)
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return target, nil
 }
 
-func runGitClone(repoURL, target string) ([]byte, error) {
-	cmd := exec.Command("git", "clone", "--", repoURL, target)
-	return cmd.CombinedOutput()
+func runGitClone(ctx context.Context, repoURL, target string, progress io.Writer) ([]byte, error) {
+	args := []string{"clone"}
+	if progress != nil {
+		args = append(args, "--progress")
+	}
+	return runGitCommand(ctx, progress, append(args, "--", repoURL, target)...)
+}
+
+// runGitCommand runs git and returns its combined output. When progress is
+// set, stderr is also streamed to it as it arrives.
+func runGitCommand(ctx context.Context, progress io.Writer, args ...string) ([]byte, error) {
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if progress != nil {
+		cmd.Stderr = io.MultiWriter(&output, progress)
+	}
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return output.Bytes(), ctxErr
+	}
+	return output.Bytes(), err
+}
+
+func gitCommandError(action string, output []byte, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	msg := gitErrorMessage(output)
+	if msg != "" {
+		return fmt.Errorf("%s: %s", action, msg)
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// gitErrorMessage drops carriage-return progress updates from git output and
+// prefers its fatal/error lines when there are any.
+func gitErrorMessage(output []byte) string {
+	var lines, failures []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if idx := strings.LastIndex(line, "\r"); idx >= 0 {
+			line = line[idx+1:]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "error:") {
+			failures = append(failures, line)
+		}
+	}
+	if len(failures) > 0 {
+		return strings.Join(failures, "\n")
+	}
+	return strings.Join(lines, "\n")
 }
 
 func resolveGitRepoRoot(source string) (string, error) {
@@ -364,111 +459,168 @@ func runGitBranchExists(repoRoot, branch string) (bool, error) {
 	return true, nil
 }
 
-func runGitWorktreeAdd(repoRoot, target, branch string) ([]byte, error) {
-	cmd := exec.Command("git", "-C", repoRoot, "worktree", "add", "-b", branch, target)
-	return cmd.CombinedOutput()
+func runGitWorktreeAdd(ctx context.Context, repoRoot, target, branch string, progress io.Writer) ([]byte, error) {
+	return runGitCommand(ctx, progress, "-C", repoRoot, "worktree", "add", "-b", branch, target)
 }
 
-func copyDir(source, target string) error {
-	return filepath.WalkDir(source, func(path string, d fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
+func listProjects(root string) ([]Project, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create hatchery root: %w", err)
+	}
 
-		rel, err := filepath.Rel(source, path)
-		if err != nil {
-			return fmt.Errorf("resolve relative path: %w", err)
-		}
-		if rel == "." {
-			return nil
-		}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("read hatchery root: %w", err)
+	}
 
-		dstPath := filepath.Join(target, rel)
-		info, err := d.Info()
-		if err != nil {
-			return fmt.Errorf("read entry metadata for %s: %w", path, err)
+	projects := make([]Project, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() == "archive" || strings.HasPrefix(entry.Name(), ".") {
+			continue
 		}
-
-		if d.IsDir() {
-			if err := os.MkdirAll(dstPath, info.Mode().Perm()); err != nil {
-				return fmt.Errorf("create directory %s: %w", dstPath, err)
-			}
-			return nil
+		// Adopted and promoted projects may be symlinks to a directory
+		// elsewhere.
+		if !entry.IsDir() && !isDirLink(filepath.Join(root, entry.Name()), entry) {
+			continue
 		}
-
-		if info.Mode()&os.ModeSymlink != 0 {
-			linkTarget, err := os.Readlink(path)
-			if err != nil {
-				return fmt.Errorf("read symlink %s: %w", path, err)
-			}
-			if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
-				return fmt.Errorf("create parent directory for symlink %s: %w", dstPath, err)
-			}
-			if err := os.Symlink(linkTarget, dstPath); err != nil {
-				return fmt.Errorf("create symlink %s: %w", dstPath, err)
-			}
-			return nil
+		project := Project{
+			Name: entry.Name(),
+			Path: filepath.Join(root, entry.Name()),
 		}
-
-		if err := copyFile(path, dstPath, info.Mode().Perm()); err != nil {
-			return err
+		if !entry.IsDir() {
+			project.Target = linkTarget(project.Path)
 		}
-		return nil
+		projects = append(projects, project)
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name > projects[j].Name
 	})
+
+	return projects, nil
 }
 
-func copyFile(source, target string, mode fs.FileMode) error {
-	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
-		return fmt.Errorf("create parent directory for file %s: %w", target, err)
+// isDirLink reports whether entry is a symlink to a directory.
+func isDirLink(path string, entry os.DirEntry) bool {
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
 	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
 
-	src, err := os.Open(source)
-	if err != nil {
-		return fmt.Errorf("open source file %s: %w", source, err)
+// linkTarget resolves a symlinked project to the directory it points at.
+func linkTarget(path string) string {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		return target
 	}
-	defer src.Close()
+	return path
+}
 
-	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
-	if err != nil {
-		return fmt.Errorf("open destination file %s: %w", target, err)
+// existingProject returns the project whose directory is named exactly name,
+// such as "2026-02-28-api", so a completed project name refers to it instead
+// of creating "<today>-2026-02-28-api".
+func existingProject(root, name string) (string, bool) {
+	if datedPrefix(name) == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	path := filepath.Join(root, name)
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return "", false
 	}
-	defer dst.Close()
+	return path, true
+}
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return fmt.Errorf("copy file %s -> %s: %w", source, target, err)
+// resolveProject finds a project by directory name ("2026-02-28-api"), by
+// name without the date ("api", newest first), or by a path inside it. An
+// empty ref means the project containing the working directory.
+func resolveProject(root, ref string) (Project, error) {
+	if isPathRef(ref) {
+		if ref == "" {
+			ref = "."
+		}
+		path, err := filepath.Abs(ref)
+		if err != nil {
+			return Project{}, fmt.Errorf("resolve project path: %w", err)
+		}
+		return projectContaining(root, path)
 	}
-	return nil
+	projects, err := listProjects(root)
+	if err != nil {
+		return Project{}, err
+	}
+	for _, project := range projects {
+		if project.Name == ref {
+			return project, nil
+		}
+	}
+	for _, project := range projects {
+		if datedPrefix(project.Name) != "" && project.Name[11:] == ref {
+			return project, nil
+		}
+	}
+	return Project{}, fmt.Errorf("%w: %s", errProjectNotFound, ref)
 }
 
-func listProjects(root string) ([]Project, error) {
-	if err := os.MkdirAll(root, 0o755); err != nil {
-		return nil, fmt.Errorf("create hatchery root: %w", err)
+// isPathRef reports whether resolveProject treats ref as a path rather than
+// a project name.
+func isPathRef(ref string) bool {
+	return ref == "" || ref == "." || ref == ".." || strings.ContainsAny(ref, `/\`)
+}
+
+// projectContaining returns the project that path lies in.
+func projectContaining(root, path string) (Project, error) {
+	resolvedRoot, resolvedPath := root, path
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		resolvedRoot = real
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		resolvedPath = real
+	}
+	rel, err := filepath.Rel(resolvedRoot, resolvedPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		if project, ok := linkedProjectContaining(root, resolvedPath); ok {
+			return project, nil
+		}
+		return Project{}, fmt.Errorf("%w: %s is not inside %s", errProjectNotFound, path, root)
 	}
+	name := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+	if name == "archive" || strings.HasPrefix(name, ".") {
+		return Project{}, fmt.Errorf("%w: %s is not inside a project", errProjectNotFound, path)
+	}
+	return Project{Name: name, Path: filepath.Join(root, name)}, nil
+}
 
+// linkedProjectContaining finds the symlinked project whose target holds
+// the resolved path.
+func linkedProjectContaining(root, resolvedPath string) (Project, bool) {
 	entries, err := os.ReadDir(root)
 	if err != nil {
-		return nil, fmt.Errorf("read hatchery root: %w", err)
+		return Project{}, false
 	}
-
-	projects := make([]Project, 0, len(entries))
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		linkPath := filepath.Join(root, entry.Name())
+		if entry.Name() == "archive" || strings.HasPrefix(entry.Name(), ".") || !isDirLink(linkPath, entry) {
 			continue
 		}
-		if entry.Name() == "archive" {
-			continue
+		if inside(resolvedPath, linkPath) {
+			return Project{Name: entry.Name(), Path: linkPath, Target: linkTarget(linkPath)}, true
 		}
-		projects = append(projects, Project{
-			Name: entry.Name(),
-			Path: filepath.Join(root, entry.Name()),
-		})
 	}
+	return Project{}, false
+}
 
-	sort.Slice(projects, func(i, j int) bool {
-		return projects[i].Name > projects[j].Name
-	})
-
-	return projects, nil
+// inside reports whether path is dir or lies under it, after resolving
+// symlinks where possible.
+func inside(path, dir string) bool {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = real
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
 }
 
 func archiveProject(root, projectPath string) (string, error) {
@@ -477,18 +629,79 @@ func archiveProject(root, projectPath string) (string, error) {
 		return "", fmt.Errorf("create archive directory: %w", err)
 	}
 
-	target := filepath.Join(archiveRoot, filepath.Base(projectPath))
-	target = nextAvailablePath(target)
-
-	if err := os.Rename(projectPath, target); err != nil {
-		return "", fmt.Errorf("archive project: %w", err)
+	var target string
+	err := withHatcheryLock(root, func() error {
+		target = nextAvailablePath(filepath.Join(archiveRoot, filepath.Base(projectPath)))
+		if err := os.Rename(projectPath, target); err != nil {
+			return fmt.Errorf("archive project: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	return target, nil
 }
 
-func removeProject(projectPath string) error {
-	if err := os.RemoveAll(projectPath); err != nil {
+// moveProjectTo moves the project at projectPath into destDir under the
+// same name and returns its new path.
+func moveProjectTo(ctx context.Context, root, projectPath, destDir string, opts moveOptions) (string, error) {
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", fmt.Errorf("create destination directory: %w", err)
+	}
+	target := filepath.Join(destDir, filepath.Base(projectPath))
+	if err := relocateDir(ctx, root, projectPath, target, opts); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// removeTreeFn deletes a project tree that has already been moved aside.
+var removeTreeFn = removeStaged
+
+// removeProject deletes a project. Under the hatchery lock it only moves
+// the project into the staging directory, which is a quick rename; the tree
+// itself is deleted after the lock is released so other hatch runs are not
+// held up by a large project. A symlinked project loses only the link; the
+// directory it points at is never touched.
+func removeProject(root, projectPath string) error {
+	var removed string
+	err := withHatcheryLock(root, func() error {
+		info, err := os.Lstat(projectPath)
+		if err != nil {
+			return fmt.Errorf("remove project: %w", err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if err := os.Remove(projectPath); err != nil {
+				return fmt.Errorf("remove project link: %w", err)
+			}
+			return nil
+		}
+		if err := os.MkdirAll(stagingDir(root), 0o755); err != nil {
+			return fmt.Errorf("create staging directory: %w", err)
+		}
+		parent, err := os.MkdirTemp(stagingDir(root), filepath.Base(projectPath)+"-removed-")
+		if err != nil {
+			return fmt.Errorf("create staging directory: %w", err)
+		}
+		// Moving a directory to a new parent needs write permission on it.
+		if mode := info.Mode().Perm(); mode&0o200 == 0 {
+			_ = os.Chmod(projectPath, mode|0o200)
+		}
+		if err := os.Rename(projectPath, filepath.Join(parent, filepath.Base(projectPath))); err != nil {
+			_ = os.Remove(parent)
+			return fmt.Errorf("remove project: %w", err)
+		}
+		removed = parent
+		return nil
+	})
+	if err != nil || removed == "" {
+		return err
+	}
+	// A failure here leaves the tree in staging, where a later run's stale
+	// cleanup removes it.
+	if err := removeTreeFn(removed); err != nil {
 		return fmt.Errorf("remove project: %w", err)
 	}
 	return nil
@@ -1,7 +1,9 @@
 package hatch
 
 import (
+	"context"
 	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -53,7 +55,7 @@ func TestCreateProject(t *testing.T) {
 	t.Parallel()
 
 	root := filepath.Join(t.TempDir(), "hatchery")
-	path, err := createProject(root, "Hatch", fixedNow())
+	path, err := createProject(root, "Hatch", fixedNow(), existsFail)
 	if err != nil {
 		t.Fatalf("createProject returned error: %v", err)
 	}
@@ -65,11 +67,53 @@ func TestCreateProject(t *testing.T) {
 		t.Fatalf("project directory should exist: %v", err)
 	}
 
-	if _, err := createProject(root, "Hatch", fixedNow()); err == nil {
+	if _, err := createProject(root, "Hatch", fixedNow(), existsFail); err == nil {
 		t.Fatalf("expected duplicate project creation to fail")
 	}
 }
 
+func TestCreateProjectOnExistsPolicies(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	first, err := createProject(root, "spike", fixedNow(), existsFail)
+	if err != nil {
+		t.Fatalf("createProject returned error: %v", err)
+	}
+
+	for _, policy := range []existsPolicy{existsFail, existsOpen} {
+		_, err := createProject(root, "spike", fixedNow(), policy)
+		var exists *projectExistsError
+		if !errors.As(err, &exists) || exists.Path != first {
+			t.Fatalf("createProject(%s) error = %v, want projectExistsError for %s", policy, err, first)
+		}
+	}
+
+	for _, want := range []string{first + "-2", first + "-3"} {
+		got, err := createProject(root, "spike", fixedNow(), existsSuffix)
+		if err != nil {
+			t.Fatalf("createProject(suffix) returned error: %v", err)
+		}
+		if got != want {
+			t.Fatalf("createProject(suffix) = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestParseExistsPolicy(t *testing.T) {
+	t.Parallel()
+
+	if got, err := parseExistsPolicy(""); err != nil || got != existsFail {
+		t.Fatalf("empty policy = %q, %v; want fail", got, err)
+	}
+	if got, err := parseExistsPolicy(" Open "); err != nil || got != existsOpen {
+		t.Fatalf("open policy = %q, %v", got, err)
+	}
+	if _, err := parseExistsPolicy("replace"); err == nil {
+		t.Fatalf("expected unknown policy to fail")
+	}
+}
+
 func TestCopyProject(t *testing.T) {
 	t.Parallel()
 
@@ -82,7 +126,7 @@ func TestCopyProject(t *testing.T) {
 		t.Fatalf("write source file: %v", err)
 	}
 
-	target, err := copyProject(root, source, "Replica", fixedNow())
+	target, _, err := copyProject(context.Background(), root, source, "Replica", fixedNow(), existsFail, copyOptions{})
 	if err != nil {
 		t.Fatalf("copyProject returned error: %v", err)
 	}
@@ -104,6 +148,7 @@ func TestListProjectsExcludesArchive(t *testing.T) {
 		"2026-02-27-older",
 		"2026-02-28-newer",
 		"archive",
+		".hatch",
 	}
 	for _, dir := range dirs {
 		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
@@ -151,6 +196,34 @@ func TestArchiveProjectCollision(t *testing.T) {
 	}
 }
 
+func TestRemoveProjectDeletesOutsideLock(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	project := filepath.Join(root, "2026-02-28-big")
+	writeTree(t, project, map[string]string{"node_modules/dep/index.js": "dep"})
+
+	hatcheryLockTimeout = 100 * time.Millisecond
+	removeTreeFn = func(path string) error {
+		// Another hatch run must be able to take the lock while the tree
+		// is being deleted.
+		if err := withHatcheryLock(root, func() error { return nil }); err != nil {
+			t.Errorf("lock held during delete: %v", err)
+		}
+		return removeStaged(path)
+	}
+	t.Cleanup(func() {
+		hatcheryLockTimeout = 10 * time.Second
+		removeTreeFn = removeStaged
+	})
+
+	if err := removeProject(root, project); err != nil {
+		t.Fatalf("removeProject returned error: %v", err)
+	}
+	if _, err := os.Stat(project); !os.IsNotExist(err) {
+		t.Fatalf("expected project to be removed, err=%v", err)
+	}
+	assertStagingEmpty(t, root)
+}
+
 func TestIsGitURL(t *testing.T) {
 	t.Parallel()
 
@@ -208,7 +281,7 @@ func TestCloneProject(t *testing.T) {
 	root := filepath.Join(t.TempDir(), "hatchery")
 
 	originalClone := gitCloneFn
-	gitCloneFn = func(repoURL, target string) ([]byte, error) {
+	gitCloneFn = func(_ context.Context, repoURL, target string, _ io.Writer) ([]byte, error) {
 		if repoURL != "https://github.com/nayeemzen/hatch.git" {
 			t.Fatalf("git clone URL = %q", repoURL)
 		}
@@ -221,7 +294,7 @@ func TestCloneProject(t *testing.T) {
 		gitCloneFn = originalClone
 	})
 
-	got, err := cloneProject(root, "https://github.com/nayeemzen/hatch.git", fixedNow())
+	got, err := cloneProject(context.Background(), root, "https://github.com/nayeemzen/hatch.git", fixedNow(), existsFail, nil)
 	if err != nil {
 		t.Fatalf("cloneProject returned error: %v", err)
 	}
@@ -261,7 +334,7 @@ func TestWorktreeProject(t *testing.T) {
 		}
 		return false, nil
 	}
-	gitWorktreeAddFn = func(repoRoot, target, branch string) ([]byte, error) {
+	gitWorktreeAddFn = func(_ context.Context, repoRoot, target, branch string, _ io.Writer) ([]byte, error) {
 		if repoRoot != source {
 			t.Fatalf("worktree repo = %q, want %q", repoRoot, source)
 		}
@@ -279,7 +352,7 @@ func TestWorktreeProject(t *testing.T) {
 		gitWorktreeAddFn = originalWorktreeAdd
 	})
 
-	got, err := worktreeProject(root, source, "Feature", fixedNow())
+	got, err := worktreeProject(context.Background(), root, source, "Feature", fixedNow(), existsFail, nil)
 	if err != nil {
 		t.Fatalf("worktreeProject returned error: %v", err)
 	}
@@ -308,7 +381,7 @@ func TestWorktreeProjectBranchCollision(t *testing.T) {
 	gitBranchExistsFn = func(_ string, branch string) (bool, error) {
 		return branch == "2026-02-28-feature", nil
 	}
-	gitWorktreeAddFn = func(_ string, target, branch string) ([]byte, error) {
+	gitWorktreeAddFn = func(_ context.Context, _ string, target, branch string, _ io.Writer) ([]byte, error) {
 		if branch != "2026-02-28-feature-2" {
 			t.Fatalf("expected branch suffix on collision, got %q", branch)
 		}
@@ -323,7 +396,7 @@ func TestWorktreeProjectBranchCollision(t *testing.T) {
 		gitWorktreeAddFn = originalWorktreeAdd
 	})
 
-	if _, err := worktreeProject(root, source, "Feature", fixedNow()); err != nil {
+	if _, err := worktreeProject(context.Background(), root, source, "Feature", fixedNow(), existsFail, nil); err != nil {
 		t.Fatalf("worktreeProject returned error: %v", err)
 	}
 }
@@ -343,8 +416,41 @@ func TestWorktreeProjectReturnsNotGitRepo(t *testing.T) {
 		gitRepoRootFn = originalRepoRoot
 	})
 
-	_, err := worktreeProject(root, source, "Feature", fixedNow())
+	_, err := worktreeProject(context.Background(), root, source, "Feature", fixedNow(), existsFail, nil)
 	if !errors.Is(err, errNotGitRepo) {
 		t.Fatalf("expected errNotGitRepo, got %v", err)
 	}
 }
+
+func TestResolveProject(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	for _, name := range []string{"2026-01-10-api", "2026-02-01-api", "2026-02-02-web", "archive/2025-12-01-api"} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(name), "src"), 0o755); err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+	}
+
+	tests := map[string]string{
+		"2026-01-10-api": "2026-01-10-api",
+		"api":            "2026-02-01-api",
+		"web":            "2026-02-02-web",
+		filepath.Join(root, "2026-01-10-api", "src"): "2026-01-10-api",
+	}
+	for ref, want := range tests {
+		project, err := resolveProject(root, ref)
+		if err != nil || project.Name != want || project.Path != filepath.Join(root, want) {
+			t.Fatalf("resolveProject(%q) = %+v, %v; want %s", ref, project, err, want)
+		}
+	}
+
+	t.Chdir(filepath.Join(root, "2026-02-02-web", "src"))
+	if project, err := resolveProject(root, ""); err != nil || project.Name != "2026-02-02-web" {
+		t.Fatalf("resolveProject from working directory = %+v, %v", project, err)
+	}
+
+	for _, ref := range []string{"missing", root, filepath.Join(root, "archive", "2025-12-01-api")} {
+		if _, err := resolveProject(root, ref); !errors.Is(err, errProjectNotFound) {
+			t.Fatalf("resolveProject(%q) error = %v, want errProjectNotFound", ref, err)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package hatch
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type promoteConfig struct {
+	// Dir is where promoted projects go, such as "~/code".
+	Dir string `json:"dir"`
+	// Symlink leaves a link in the hatchery pointing at the new location.
+	Symlink bool `json:"symlink"`
+}
+
+const promoteUsage = "usage: hatch promote [project] [dest] [--link]"
+
+type promoteOptions struct {
+	link bool
+}
+
+func newPromoteFlagSet(options *promoteOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("promote", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&options.link, "link", false, "leave a symlink in the hatchery")
+	return fs
+}
+
+// promoteTarget works out where project goes. dest works like mv's: an
+// existing directory receives the project, anything else is the new path.
+// Without dest, the project goes into promote.dir. Either way it loses its
+// date prefix.
+func promoteTarget(cfg config, project Project, dest string) (string, error) {
+	name := undatedName(project.Name)
+	if dest == "" {
+		if cfg.Promote.Dir == "" {
+			return "", errors.New("no destination: pass one or set promote.dir in the config")
+		}
+		dir, err := expandPath(cfg.Promote.Dir)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, name), nil
+	}
+	target, err := expandPath(dest)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		return filepath.Join(target, name), nil
+	}
+	return target, nil
+}
+
+// promoteProject moves the project out of the hatchery to target and
+// records where it went.
+func promoteProject(ctx context.Context, root string, project Project, target string, link bool, now time.Time, opts moveOptions) error {
+	if info, err := os.Lstat(project.Path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return fmt.Errorf("%s already lives outside the hatchery at %s", project.Name, linkTarget(project.Path))
+	}
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("%s already exists", target)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("create destination directory: %w", err)
+	}
+	if link {
+		opts.vacated = func() error {
+			if err := os.Symlink(target, project.Path); err != nil {
+				return fmt.Errorf("link promoted project: %w", err)
+			}
+			return nil
+		}
+	}
+	if err := relocateDir(ctx, root, project.Path, target, opts); err != nil {
+		return err
+	}
+	return updateMetadata(root, func(md *metadata) error {
+		meta := md.project(project.Path)
+		meta.PromotedTo = target
+		meta.PromotedAt = now
+		md.setProject(project.Path, meta)
+		return nil
+	})
+}
+
+// moveOptions tunes relocateDir.
+type moveOptions struct {
+	// progress, when set, receives copy progress for a move to another
+	// filesystem.
+	progress func(copyProgress)
+	// warn, when set, receives problems that do not undo a finished move,
+	// such as an original that could not be removed.
+	warn func(string)
+	// vacated, when set, runs under the hatchery lock once source is gone,
+	// such as to leave a link in its place. Its error is a warning, since
+	// the move itself has happened.
+	vacated func() error
+}
+
+// renameFn is replaced in tests to simulate a move across filesystems.
+var renameFn = os.Rename
+
+// relocateDir moves source to target, claiming target under the hatchery
+// lock on root. Across filesystems, where a rename fails, the tree is copied
+// next to target with its times, permissions, and hardlinks before the lock
+// is taken, so the lock only covers the renames that put it in place and
+// set source aside. A symlink is recreated instead, and a tree the copy
+// cannot fully reproduce stays where it is. Once target is in place the
+// move has happened; an original that cannot be removed is a warning.
+//
+// git records worktree locations as absolute paths, so once moved, a linked
+// worktree, or a repository with linked worktrees, gets git worktree repair
+// to point both sides at the new location again.
+func relocateDir(ctx context.Context, root, source, target string, opts moveOptions) error {
+	claim := func() error {
+		if _, err := os.Lstat(target); err == nil {
+			return &projectExistsError{Path: target}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	warn := func(message string) {
+		if opts.warn != nil {
+			opts.warn(message)
+		}
+	}
+	vacated := func() {
+		if opts.vacated == nil {
+			return
+		}
+		if err := opts.vacated(); err != nil {
+			warn(err.Error())
+		}
+	}
+
+	crossed := false
+	err := withHatcheryLock(root, func() error {
+		if err := claim(); err != nil {
+			return err
+		}
+		err := renameFn(source, target)
+		if err != nil && !errors.Is(err, errCrossDevice) {
+			return fmt.Errorf("move project: %w", err)
+		}
+		if err != nil {
+			dest, linkErr := os.Readlink(source)
+			if linkErr != nil {
+				crossed = true
+				return nil
+			}
+			if err := os.Symlink(dest, target); err != nil {
+				return fmt.Errorf("move project: %w", err)
+			}
+			if err := os.Remove(source); err != nil {
+				warn(fmt.Sprintf("moved %s to %s, but could not remove the original link: %v", source, target, err))
+				return nil
+			}
+		}
+		vacated()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if crossed {
+		if err := copyAcross(ctx, root, source, target, opts.progress, claim, vacated, warn); err != nil {
+			return err
+		}
+	}
+
+	gitDir, err := os.Lstat(filepath.Join(target, ".git"))
+	linked := err == nil && gitDir.Mode().IsRegular()
+	if info, err := os.Stat(filepath.Join(target, ".git", "worktrees")); linked || (err == nil && info.IsDir()) {
+		output, err := runGitCommand(context.Background(), nil, "-C", target, "worktree", "repair")
+		if err != nil {
+			return gitCommandError("repair worktrees", output, err)
+		}
+	}
+	return nil
+}
+
+// copyAcross is relocateDir's move to another filesystem. The copy is
+// staged in a hidden directory next to target, and source is renamed into
+// a hidden directory next to itself, which keeps both renames on one
+// filesystem and quick enough for the lock.
+func copyAcross(ctx context.Context, root, source, target string, progress func(copyProgress), claim func() error, vacated func(), warn func(string)) error {
+	parent, err := os.MkdirTemp(filepath.Dir(target), "."+filepath.Base(target)+"-")
+	if err != nil {
+		return fmt.Errorf("copy across filesystems: %w", err)
+	}
+	defer removeStaged(parent)
+	staged := filepath.Join(parent, filepath.Base(target))
+	opts := copyOptions{strategy: strategyAuto, ignore: ignoreRules{disabled: true}, preserve: true, progress: progress}
+	result, err := copyDir(ctx, source, staged, opts)
+	if err != nil {
+		return fmt.Errorf("copy across filesystems: %w", err)
+	}
+	if len(result.Skipped) > 0 {
+		return fmt.Errorf("copy across filesystems: cannot reproduce %s", result.Skipped[0])
+	}
+
+	var aside string
+	err = withHatcheryLock(root, func() error {
+		if err := claim(); err != nil {
+			return err
+		}
+		if err := renameStaged(staged, target); err != nil {
+			return err
+		}
+		asideParent, err := os.MkdirTemp(filepath.Dir(source), "."+filepath.Base(source)+"-")
+		if err == nil {
+			if err = os.Rename(source, filepath.Join(asideParent, filepath.Base(source))); err != nil {
+				_ = os.Remove(asideParent)
+			}
+		}
+		if err != nil {
+			warn(fmt.Sprintf("copied %s to %s, but could not remove the original: %v", source, target, err))
+			return nil
+		}
+		aside = asideParent
+		vacated()
+		return nil
+	})
+	if aside != "" {
+		if removeErr := removeTreeFn(aside); removeErr != nil {
+			warn(fmt.Sprintf("moved %s to %s, but could not remove all of the original in %s: %v", source, target, aside, removeErr))
+		}
+	}
+	return err
+}
+
+// runPromote graduates a project to a permanent location.
+func runPromote(c commandContext, args []string) error {
+	var options promoteOptions
+	fs := newPromoteFlagSet(&options)
+	refs, err := parseCommandFlags(fs, args, promoteUsage)
+	if err != nil {
+		return err
+	}
+	if len(refs) > 2 {
+		return errors.New(promoteUsage)
+	}
+	refs = append(refs, "", "")
+	project, err := c.resolveProject(refs[0])
+	if err != nil {
+		return err
+	}
+	target, err := promoteTarget(c.cfg, project, refs[1])
+	if err != nil {
+		return err
+	}
+	progress := newTerminalProgress(c.errOut, time.Now)
+	opts := moveOptions{progress: progress.copy, warn: func(message string) {
+		progress.finish()
+		fmt.Fprintln(c.errOut, "warning: "+message)
+	}}
+	err = promoteProject(context.Background(), filepath.Dir(project.Path), project, target, options.link || c.cfg.Promote.Symlink, c.now(), opts)
+	progress.finish()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render("Promoted "+project.Name+" to "+target))
+	return nil
+}
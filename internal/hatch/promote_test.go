@@ -0,0 +1,244 @@
+package hatch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPromoteTarget(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	project := Project{Name: "2026-02-28-payments", Path: "/h/2026-02-28-payments"}
+	cfg := config{Promote: promoteConfig{Dir: dir}}
+
+	tests := []struct {
+		cfg  config
+		dest string
+		want string
+	}{
+		{cfg: cfg, want: filepath.Join(dir, "payments")},
+		{dest: dir, want: filepath.Join(dir, "payments")},
+		{dest: filepath.Join(dir, "billing"), want: filepath.Join(dir, "billing")},
+	}
+	for _, tt := range tests {
+		if got, err := promoteTarget(tt.cfg, project, tt.dest); err != nil || got != tt.want {
+			t.Fatalf("promoteTarget(%q) = %q, %v; want %q", tt.dest, got, err, tt.want)
+		}
+	}
+	if _, err := promoteTarget(config{}, project, ""); err == nil {
+		t.Fatal("expected error without a destination")
+	}
+}
+
+func TestRunPromoteKeepsWorktreesWorking(t *testing.T) {
+	requireGit(t)
+	root := filepath.Join(t.TempDir(), "hatchery")
+	code := filepath.Join(t.TempDir(), "code")
+	t.Setenv("HATCHERY_HOME", root)
+	t.Setenv("HATCH_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+
+	repo := filepath.Join(root, "2026-01-01-repo")
+	worktree := filepath.Join(root, "2026-01-02-repo-wt")
+	runGit(t, "", "init", "--quiet", "-b", "main", repo)
+	writeTree(t, repo, map[string]string{"README.md": "hi"})
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "--quiet", "-m", "init")
+	runGit(t, repo, "worktree", "add", "--quiet", "-b", "wt", worktree)
+	if err := os.MkdirAll(code, 0o755); err != nil {
+		t.Fatalf("create code dir: %v", err)
+	}
+	if err := setProjectTags(root, repo, []string{"keep"}); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+
+	promote := func(args ...string) string {
+		t.Helper()
+		out := new(bytes.Buffer)
+		if err := run(append([]string{"promote"}, args...), strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("promote %v returned error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	// The main repository moves; its linked worktree is repaired.
+	if out := promote("repo", code, "--link"); !strings.Contains(out, "Promoted 2026-01-01-repo to "+filepath.Join(code, "repo")) {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if link, err := os.Readlink(repo); err != nil || link != filepath.Join(code, "repo") {
+		t.Fatalf("expected a symlink to the new location, got %q, %v", link, err)
+	}
+	if got := gitOutput(t, worktree, "rev-parse", "--git-common-dir"); got != filepath.Join(code, "repo", ".git") {
+		t.Fatalf("worktree points at %q after promoting its repository", got)
+	}
+	md, _ := loadMetadata(root)
+	if meta := md.project(repo); meta.PromotedTo != filepath.Join(code, "repo") || !meta.PromotedAt.Equal(fixedNow()) || len(meta.Tags) != 1 {
+		t.Fatalf("expected a breadcrumb next to the existing metadata, got %+v", meta)
+	}
+
+	// A moved linked worktree is repaired as well.
+	promote("2026-01-02-repo-wt", filepath.Join(code, "repo-wt"))
+	if _, err := os.Lstat(worktree); !os.IsNotExist(err) {
+		t.Fatalf("expected no link without --link, stat err = %v", err)
+	}
+	if list := gitOutput(t, filepath.Join(code, "repo"), "worktree", "list"); !strings.Contains(list, filepath.Join(code, "repo-wt")) {
+		t.Fatalf("repository does not know the moved worktree:\n%s", list)
+	}
+	if got := gitOutput(t, filepath.Join(code, "repo-wt"), "branch", "--show-current"); got != "wt" {
+		t.Fatalf("moved worktree is on %q", got)
+	}
+}
+
+func TestRelocateDirAcrossFilesystems(t *testing.T) {
+	requireGit(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits and hardlinks are not preserved on windows")
+	}
+	renameFn = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errCrossDevice}
+	}
+	t.Cleanup(func() { renameFn = os.Rename })
+
+	base := t.TempDir()
+	repo := filepath.Join(base, "repo")
+	worktree := filepath.Join(base, "repo-wt")
+	runGit(t, "", "init", "--quiet", "-b", "main", repo)
+	writeTree(t, repo, map[string]string{"README.md": "hi", "bin/tool": "#!/bin/sh\n"})
+	if err := os.Chmod(filepath.Join(repo, "bin", "tool"), 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	if err := os.Link(filepath.Join(repo, "README.md"), filepath.Join(repo, "README.link")); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	runGit(t, repo, "add", "README.md", "bin")
+	runGit(t, repo, "commit", "--quiet", "-m", "init")
+	runGit(t, repo, "worktree", "add", "--quiet", "-b", "wt", worktree)
+
+	moved := filepath.Join(base, "elsewhere", "repo")
+	if err := os.MkdirAll(filepath.Dir(moved), 0o755); err != nil {
+		t.Fatalf("create destination: %v", err)
+	}
+	// Other hatch runs must not wait on the lock while a tree is copied.
+	previousTimeout := hatcheryLockTimeout
+	hatcheryLockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { hatcheryLockTimeout = previousTimeout })
+	lockErr := errors.New("no copy progress reported")
+	var lockOnce sync.Once
+	opts := moveOptions{progress: func(copyProgress) {
+		lockOnce.Do(func() { lockErr = withHatcheryLock(base, func() error { return nil }) })
+	}}
+	if err := relocateDir(context.Background(), base, repo, moved, opts); err != nil {
+		t.Fatalf("relocateDir(repo) returned error: %v", err)
+	}
+	if lockErr != nil {
+		t.Fatalf("expected the hatchery to stay unlocked during the copy: %v", lockErr)
+	}
+	if _, err := os.Lstat(repo); !os.IsNotExist(err) {
+		t.Fatalf("expected the source to be removed, stat err = %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(moved, "bin", "tool")); err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("expected the copy to keep permissions, got %v, %v", info, err)
+	}
+	a, _ := os.Stat(filepath.Join(moved, "README.md"))
+	b, _ := os.Stat(filepath.Join(moved, "README.link"))
+	if a == nil || b == nil || !os.SameFile(a, b) {
+		t.Fatal("expected the copy to keep hardlinks")
+	}
+	if got := gitOutput(t, worktree, "rev-parse", "--git-common-dir"); got != filepath.Join(moved, ".git") {
+		t.Fatalf("worktree points at %q after its repository was copied", got)
+	}
+
+	movedWorktree := filepath.Join(base, "elsewhere", "repo-wt")
+	if err := relocateDir(context.Background(), base, worktree, movedWorktree, moveOptions{}); err != nil {
+		t.Fatalf("relocateDir(worktree) returned error: %v", err)
+	}
+	if list := gitOutput(t, moved, "worktree", "list"); !strings.Contains(list, movedWorktree) {
+		t.Fatalf("repository does not know the copied worktree:\n%s", list)
+	}
+	if got := gitOutput(t, movedWorktree, "branch", "--show-current"); got != "wt" {
+		t.Fatalf("copied worktree is on %q", got)
+	}
+}
+
+func TestRunPromoteAcrossFilesystemsWarnsWhenOriginalStays(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	code := filepath.Join(t.TempDir(), "code")
+	t.Setenv("HATCHERY_HOME", root)
+	t.Setenv("HATCH_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	path := filepath.Join(root, "2026-01-01-api")
+	writeTree(t, path, map[string]string{"main.go": "package main"})
+	if err := os.MkdirAll(code, 0o755); err != nil {
+		t.Fatalf("create code dir: %v", err)
+	}
+
+	renameFn = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errCrossDevice}
+	}
+	removeTreeFn = func(string) error { return errors.New("device busy") }
+	t.Cleanup(func() {
+		renameFn = os.Rename
+		removeTreeFn = removeStaged
+	})
+
+	errOut := new(bytes.Buffer)
+	if err := run([]string{"promote", "api", code, "--link"}, strings.NewReader(""), new(bytes.Buffer), errOut, fixedNow); err != nil {
+		t.Fatalf("promote returned error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "warning: ") || !strings.Contains(errOut.String(), "device busy") {
+		t.Fatalf("expected a warning about the original, got %q", errOut.String())
+	}
+	target := filepath.Join(code, "api")
+	if _, err := os.Stat(filepath.Join(target, "main.go")); err != nil {
+		t.Fatalf("expected the project at its new location: %v", err)
+	}
+	if got, err := os.Readlink(path); err != nil || got != target {
+		t.Fatalf("expected a link in the hatchery, got %q, %v", got, err)
+	}
+	md, err := loadMetadata(root)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if md.project(path).PromotedTo != target {
+		t.Fatalf("expected the promotion to be recorded, got %+v", md.project(path))
+	}
+}
+
+func TestBrowserPromoteAction(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	code := filepath.Join(t.TempDir(), "code")
+	path := filepath.Join(root, "2026-02-28-api")
+	writeTree(t, path, map[string]string{"main.go": ""})
+
+	model := newBrowserModelWithClock(root, []Project{{Name: "2026-02-28-api", Path: path}}, fixedNow)
+	model.config.Promote.Dir = code
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	model = updated.(browserModel)
+	if model.promptInput != filepath.Join(code, "api") {
+		t.Fatalf("prompt = %q, want the configured destination", model.promptInput)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+	if model.task == nil {
+		t.Fatalf("expected the promote to run in the background, status %q", model.status)
+	}
+	model = finishTask(t, model)
+
+	if _, err := os.Stat(filepath.Join(code, "api", "main.go")); err != nil {
+		t.Fatalf("expected the project to move: %v (status %q)", err, model.status)
+	}
+	if len(model.projects) != 0 || !strings.HasPrefix(model.status, "Promoted 2026-02-28-api") {
+		t.Fatalf("projects %v, status %q", model.projects, model.status)
+	}
+}
@@ -0,0 +1,297 @@
+package hatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidPullRequest = errors.New("pull request reference must be a PR/MR URL or <repo>#<number>")
+
+var gitFetchRefFn = runGitFetchRef
+var gitCheckoutNewBranchFn = runGitCheckoutNewBranch
+var gitWorktreeAddRefFn = runGitWorktreeAddRef
+var gitRemoteURLFn = runGitRemoteURL
+
+type pullRequestProvider int
+
+const (
+	providerUnknown pullRequestProvider = iota
+	providerGitHub
+	providerGitLab
+)
+
+type pullRequestRef struct {
+	Repo     string
+	Number   int
+	Provider pullRequestProvider
+}
+
+// parsePullRequestRef recognizes GitHub pull request URLs, GitLab merge
+// request URLs, and the <repo>#<number> shorthand where <repo> is a git URL
+// or a local checkout.
+func parsePullRequestRef(raw string) (pullRequestRef, bool) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return pullRequestRef{}, false
+	}
+
+	// URLs come first: one copied from the browser may carry a fragment
+	// such as #issuecomment-1 that is not the shorthand's number.
+	if ref, ok := parsePullRequestURL(value); ok {
+		return ref, true
+	}
+
+	if idx := strings.LastIndex(value, "#"); idx > 0 {
+		number, err := strconv.Atoi(value[idx+1:])
+		if err != nil || number <= 0 {
+			return pullRequestRef{}, false
+		}
+		repo := value[:idx]
+		provider := providerUnknown
+		if isGitURL(repo) {
+			provider = providerFromHost(gitURLHost(repo))
+		} else if !isLocalDir(repo) {
+			return pullRequestRef{}, false
+		}
+		return pullRequestRef{Repo: repo, Number: number, Provider: provider}, true
+	}
+	return pullRequestRef{}, false
+}
+
+// parsePullRequestURL recognizes GitHub pull request and GitLab merge
+// request URLs, ignoring any query or fragment.
+func parsePullRequestURL(value string) (pullRequestRef, bool) {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" || !strings.EqualFold(parsed.Scheme, "https") {
+		return pullRequestRef{}, false
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i, segment := range segments {
+		if i+1 >= len(segments) {
+			break
+		}
+		number, err := strconv.Atoi(segments[i+1])
+		if err != nil || number <= 0 {
+			continue
+		}
+		switch {
+		case segment == "pull" && i >= 2:
+			repo := fmt.Sprintf("https://%s/%s.git", parsed.Host, strings.Join(segments[:i], "/"))
+			return pullRequestRef{Repo: repo, Number: number, Provider: providerGitHub}, true
+		case segment == "merge_requests" && i >= 3 && segments[i-1] == "-":
+			repo := fmt.Sprintf("https://%s/%s.git", parsed.Host, strings.Join(segments[:i-1], "/"))
+			return pullRequestRef{Repo: repo, Number: number, Provider: providerGitLab}, true
+		}
+	}
+	return pullRequestRef{}, false
+}
+
+func isLocalDir(path string) bool {
+	resolved, err := expandPath(path)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(resolved)
+	return err == nil && info.IsDir()
+}
+
+func gitURLHost(raw string) string {
+	value := strings.TrimSpace(raw)
+	if gitSCPURLPattern.MatchString(value) {
+		host := strings.SplitN(value, ":", 2)[0]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		return host
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+func providerFromHost(host string) pullRequestProvider {
+	if strings.Contains(strings.ToLower(host), "gitlab") {
+		return providerGitLab
+	}
+	return providerGitHub
+}
+
+// remoteRef returns the ref the hosting provider publishes for the request.
+func (ref pullRequestRef) remoteRef() string {
+	if ref.Provider == providerGitLab {
+		return fmt.Sprintf("refs/merge-requests/%d/head", ref.Number)
+	}
+	return fmt.Sprintf("refs/pull/%d/head", ref.Number)
+}
+
+// branchName and dirName use "pr" for GitLab merge requests as well, so
+// every checkout lands in <repo>-pr<number> on a pr-<number> branch.
+func (ref pullRequestRef) branchName() string {
+	return fmt.Sprintf("pr-%d", ref.Number)
+}
+
+func (ref pullRequestRef) dirName(repoName string, now time.Time) (string, error) {
+	return projectDirName(fmt.Sprintf("%s-pr%d", repoName, ref.Number), now)
+}
+
+func pullRequestProject(ctx context.Context, root string, ref pullRequestRef, now time.Time, onExists existsPolicy, progress io.Writer) (string, error) {
+	// A bare repository has no work tree to add worktrees next to, so it is
+	// cloned like a remote.
+	if isGitURL(ref.Repo) || isBareRepo(ref.Repo) {
+		return clonePullRequest(ctx, root, ref, now, onExists, progress)
+	}
+	return worktreePullRequest(ctx, root, ref, now, onExists, progress)
+}
+
+// clonePullRequest clones ref.Repo and checks the request head out on a
+// local branch. ref.Repo may be any location git clone accepts.
+func clonePullRequest(ctx context.Context, root string, ref pullRequestRef, now time.Time, onExists existsPolicy, progress io.Writer) (string, error) {
+	repoName, err := repoNameFromGitURL(ref.Repo)
+	if err != nil {
+		repoName, err = normalizeName(strings.TrimSuffix(filepath.Base(strings.TrimRight(ref.Repo, "/")), ".git"))
+		if err != nil {
+			return "", errInvalidPullRequest
+		}
+	}
+	if ref.Provider == providerUnknown {
+		ref.Provider = providerFromHost(gitURLHost(ref.Repo))
+	}
+
+	dirName, err := ref.dirName(repoName, now)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", fmt.Errorf("create hatchery root: %w", err)
+	}
+
+	target, err := stageProject(root, filepath.Join(root, dirName), onExists, func(staged string) error {
+		if output, err := gitCloneFn(ctx, ref.Repo, staged, progress); err != nil {
+			return gitCommandError("clone repository", output, err)
+		}
+		if output, err := gitFetchRefFn(ctx, staged, "origin", ref.remoteRef(), progress); err != nil {
+			return gitCommandError(fmt.Sprintf("fetch %s", ref.remoteRef()), output, err)
+		}
+		if output, err := gitCheckoutNewBranchFn(ctx, staged, ref.branchName(), "FETCH_HEAD"); err != nil {
+			return gitCommandError("check out pull request", output, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return target, nil
+}
+
+// worktreePullRequest fetches the request head from the origin remote of a
+// local checkout and adds a worktree for it, avoiding a fresh clone.
+func worktreePullRequest(ctx context.Context, root string, ref pullRequestRef, now time.Time, onExists existsPolicy, progress io.Writer) (string, error) {
+	resolvedSource, err := expandPath(ref.Repo)
+	if err != nil {
+		return "", err
+	}
+
+	sourceInfo, err := os.Stat(resolvedSource)
+	if err != nil {
+		return "", fmt.Errorf("read source directory: %w", err)
+	}
+	if !sourceInfo.IsDir() {
+		return "", fmt.Errorf("source must be a directory: %s", resolvedSource)
+	}
+
+	repoRoot, err := gitRepoRootFn(resolvedSource)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Provider == providerUnknown {
+		ref.Provider = providerGitHub
+		if remote, err := gitRemoteURLFn(repoRoot, "origin"); err == nil {
+			ref.Provider = providerFromHost(gitURLHost(remote))
+		}
+	}
+
+	repoName, err := normalizeName(filepath.Base(repoRoot))
+	if err != nil {
+		return "", err
+	}
+	dirName, err := ref.dirName(repoName, now)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", fmt.Errorf("create hatchery root: %w", err)
+	}
+
+	target, err := claimProjectDir(root, filepath.Join(root, dirName), onExists)
+	if err != nil {
+		return "", err
+	}
+
+	branchName, err := nextAvailableBranchName(repoRoot, ref.branchName())
+	if err != nil {
+		_ = os.RemoveAll(target)
+		return "", err
+	}
+
+	if output, err := gitFetchRefFn(ctx, repoRoot, "origin", ref.remoteRef(), progress); err != nil {
+		_ = os.RemoveAll(target)
+		return "", gitCommandError(fmt.Sprintf("fetch %s", ref.remoteRef()), output, err)
+	}
+	if output, err := gitWorktreeAddRefFn(ctx, repoRoot, target, branchName, "FETCH_HEAD", progress); err != nil {
+		_ = os.RemoveAll(target)
+		return "", gitCommandError("create git worktree", output, err)
+	}
+
+	return target, nil
+}
+
+func runGitFetchRef(ctx context.Context, repoRoot, remote, ref string, progress io.Writer) ([]byte, error) {
+	args := []string{"-C", repoRoot, "fetch"}
+	if progress != nil {
+		args = append(args, "--progress")
+	}
+	return runGitCommand(ctx, progress, append(args, "--", remote, ref)...)
+}
+
+func runGitCheckoutNewBranch(ctx context.Context, repoRoot, branch, startPoint string) ([]byte, error) {
+	return runGitCommand(ctx, nil, "-C", repoRoot, "checkout", "-b", branch, startPoint)
+}
+
+func runGitWorktreeAddRef(ctx context.Context, repoRoot, target, branch, startPoint string, progress io.Writer) ([]byte, error) {
+	return runGitCommand(ctx, progress, "-C", repoRoot, "worktree", "add", "-b", branch, target, startPoint)
+}
+
+// isBareRepo reports whether path is a local bare git repository.
+func isBareRepo(path string) bool {
+	resolved, err := expandPath(path)
+	if err != nil {
+		return false
+	}
+	output, err := exec.Command("git", "-C", resolved, "rev-parse", "--is-bare-repository").Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+func runGitRemoteURL(repoRoot, remote string) (string, error) {
+	cmd := exec.Command("git", "-C", repoRoot, "remote", "get-url", remote)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,180 @@
+package hatch
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePullRequestRef(t *testing.T) {
+	t.Parallel()
+
+	localRepo := t.TempDir()
+	tests := []struct {
+		input    string
+		wantOK   bool
+		repo     string
+		number   int
+		provider pullRequestProvider
+	}{
+		{input: "https://github.com/nayeemzen/hatch/pull/123", wantOK: true, repo: "https://github.com/nayeemzen/hatch.git", number: 123, provider: providerGitHub},
+		{input: "https://github.com/nayeemzen/hatch/pull/123/files", wantOK: true, repo: "https://github.com/nayeemzen/hatch.git", number: 123, provider: providerGitHub},
+		{input: "https://github.com/nayeemzen/hatch/pull/42#issuecomment-1", wantOK: true, repo: "https://github.com/nayeemzen/hatch.git", number: 42, provider: providerGitHub},
+		{input: "https://github.com/nayeemzen/hatch/pull/42?w=1#discussion_r9", wantOK: true, repo: "https://github.com/nayeemzen/hatch.git", number: 42, provider: providerGitHub},
+		{input: "https://gitlab.com/group/sub/tool/-/merge_requests/45", wantOK: true, repo: "https://gitlab.com/group/sub/tool.git", number: 45, provider: providerGitLab},
+		{input: "git@github.com:nayeemzen/hatch.git#9", wantOK: true, repo: "git@github.com:nayeemzen/hatch.git", number: 9, provider: providerGitHub},
+		{input: "https://gitlab.example.com/team/app.git#3", wantOK: true, repo: "https://gitlab.example.com/team/app.git", number: 3, provider: providerGitLab},
+		{input: localRepo + "#12", wantOK: true, repo: localRepo, number: 12, provider: providerUnknown},
+		{input: "https://github.com/nayeemzen/hatch.git", wantOK: false},
+		{input: "https://github.com/nayeemzen/hatch/issues/4", wantOK: false},
+		{input: "spike#2", wantOK: false},
+		{input: "https://github.com/nayeemzen/hatch.git#main", wantOK: false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+			got, ok := parsePullRequestRef(tc.input)
+			if ok != tc.wantOK {
+				t.Fatalf("parsePullRequestRef(%q) ok = %v, want %v", tc.input, ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.Repo != tc.repo || got.Number != tc.number || got.Provider != tc.provider {
+				t.Fatalf("parsePullRequestRef(%q) = %+v", tc.input, got)
+			}
+		})
+	}
+}
+
+func TestPullRequestNamingUsesPRForEveryHost(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)
+	for _, provider := range []pullRequestProvider{providerGitHub, providerGitLab, providerUnknown} {
+		ref := pullRequestRef{Number: 45, Provider: provider}
+		if got := ref.branchName(); got != "pr-45" {
+			t.Fatalf("branchName for provider %v = %q, want pr-45", provider, got)
+		}
+		got, err := ref.dirName("tool", now)
+		if err != nil {
+			t.Fatalf("dirName for provider %v returned error: %v", provider, err)
+		}
+		if got != "2026-02-28-tool-pr45" {
+			t.Fatalf("dirName for provider %v = %q, want 2026-02-28-tool-pr45", provider, got)
+		}
+	}
+}
+
+func TestClonePullRequestFromBareRepo(t *testing.T) {
+	upstream, prHead := newPullRequestFixture(t)
+	root := filepath.Join(t.TempDir(), "hatchery")
+
+	// Go through the same dispatch as "hatch <bare-repo>#7".
+	ref, ok := parsePullRequestRef(upstream + "#7")
+	if !ok {
+		t.Fatalf("parsePullRequestRef(%q) not recognized", upstream+"#7")
+	}
+	got, err := pullRequestProject(context.Background(), root, ref, fixedNow(), existsFail, nil)
+	if err != nil {
+		t.Fatalf("pullRequestProject returned error: %v", err)
+	}
+
+	want := filepath.Join(root, "2026-02-28-upstream-pr7")
+	if got != want {
+		t.Fatalf("pullRequestProject path = %q, want %q", got, want)
+	}
+	if branch := gitOutput(t, got, "rev-parse", "--abbrev-ref", "HEAD"); branch != "pr-7" {
+		t.Fatalf("checked out branch = %q, want pr-7", branch)
+	}
+	if head := gitOutput(t, got, "rev-parse", "HEAD"); head != prHead {
+		t.Fatalf("HEAD = %q, want PR head %q", head, prHead)
+	}
+}
+
+func TestWorktreePullRequestFromLocalCheckout(t *testing.T) {
+	upstream, prHead := newPullRequestFixture(t)
+	checkout := filepath.Join(t.TempDir(), "service")
+	runGit(t, "", "clone", "--quiet", upstream, checkout)
+	root := filepath.Join(t.TempDir(), "hatchery")
+
+	got, err := pullRequestProject(context.Background(), root, pullRequestRef{Repo: checkout, Number: 7}, fixedNow(), existsFail, nil)
+	if err != nil {
+		t.Fatalf("pullRequestProject returned error: %v", err)
+	}
+
+	want := filepath.Join(root, "2026-02-28-service-pr7")
+	if got != want {
+		t.Fatalf("pullRequestProject path = %q, want %q", got, want)
+	}
+	if head := gitOutput(t, got, "rev-parse", "HEAD"); head != prHead {
+		t.Fatalf("worktree HEAD = %q, want PR head %q", head, prHead)
+	}
+	if branch := gitOutput(t, got, "rev-parse", "--abbrev-ref", "HEAD"); branch != "pr-7" {
+		t.Fatalf("worktree branch = %q, want pr-7", branch)
+	}
+}
+
+// newPullRequestFixture creates a bare repository whose refs/pull/7/head
+// points at a commit that is not on any branch, like a hosted PR ref.
+func newPullRequestFixture(t *testing.T) (string, string) {
+	t.Helper()
+	requireGit(t)
+
+	base := t.TempDir()
+	seed := filepath.Join(base, "seed")
+	upstream := filepath.Join(base, "upstream.git")
+
+	runGit(t, "", "init", "--quiet", "-b", "main", seed)
+	if err := os.WriteFile(filepath.Join(seed, "README.md"), []byte("main"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seed, "add", ".")
+	runGit(t, seed, "commit", "--quiet", "-m", "main")
+	runGit(t, seed, "checkout", "--quiet", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(seed, "README.md"), []byte("feature"), 0o644); err != nil {
+		t.Fatalf("write feature file: %v", err)
+	}
+	runGit(t, seed, "commit", "--quiet", "-am", "feature")
+	prHead := gitOutput(t, seed, "rev-parse", "HEAD")
+
+	runGit(t, "", "init", "--quiet", "--bare", "-b", "main", upstream)
+	runGit(t, seed, "push", "--quiet", upstream, "main:refs/heads/main", "feature:refs/pull/7/head")
+	return upstream, prHead
+}
+
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	gitOutput(t, dir, args...)
+}
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=hatch", "GIT_AUTHOR_EMAIL=hatch@example.com",
+		"GIT_COMMITTER_NAME=hatch", "GIT_COMMITTER_EMAIL=hatch@example.com",
+		"GIT_CONFIG_NOSYSTEM=1",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,147 @@
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var multiplexerOutputFn = runMultiplexerOutput
+var multiplexerAttachFn = runMultiplexerAttach
+
+type multiplexer string
+
+const (
+	multiplexerTmux   multiplexer = "tmux"
+	multiplexerZellij multiplexer = "zellij"
+)
+
+type sessionConfig struct {
+	// Multiplexer is tmux (the default) or zellij.
+	Multiplexer string `json:"multiplexer"`
+	// Layout sets up new sessions: a file of tmux commands, sourced in the
+	// new session, or a zellij layout file.
+	Layout string `json:"layout"`
+}
+
+func parseMultiplexer(value string) (multiplexer, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "tmux":
+		return multiplexerTmux, nil
+	case "zellij":
+		return multiplexerZellij, nil
+	default:
+		return "", fmt.Errorf("unknown multiplexer %q (use tmux or zellij)", value)
+	}
+}
+
+var invalidSessionChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// sessionName derives a session name from the project directory. tmux does
+// not allow "." or ":" in session names, so anything unusual becomes "-".
+func sessionName(projectPath string) string {
+	return strings.Trim(invalidSessionChars.ReplaceAllString(filepath.Base(projectPath), "-"), "-")
+}
+
+// openSession creates a session for projectPath unless one already exists,
+// then attaches to it. Inside tmux it switches the current client instead of
+// nesting.
+func openSession(program multiplexer, cfg sessionConfig, projectPath string, in io.Reader, out, errOut io.Writer) error {
+	layout := ""
+	if strings.TrimSpace(cfg.Layout) != "" {
+		var err error
+		if layout, err = expandPath(cfg.Layout); err != nil {
+			return err
+		}
+	}
+	name := sessionName(projectPath)
+	if name == "" {
+		return fmt.Errorf("cannot derive a session name from %s", projectPath)
+	}
+
+	switch program {
+	case multiplexerZellij:
+		return openZellijSession(name, layout, projectPath, in, out, errOut)
+	default:
+		if err := ensureTmuxSession(name, layout, projectPath); err != nil {
+			return err
+		}
+		if os.Getenv("TMUX") != "" {
+			if output, err := multiplexerOutputFn(projectPath, "tmux", "switch-client", "-t", "="+name); err != nil {
+				return multiplexerError("switch tmux client", output, err)
+			}
+			return nil
+		}
+		return multiplexerAttachFn(projectPath, in, out, errOut, "tmux", "attach-session", "-t", "="+name)
+	}
+}
+
+// ensureTmuxSession starts a detached session in projectPath and sources the
+// layout file in it, unless a session with that name is already running.
+func ensureTmuxSession(name, layout, projectPath string) error {
+	if _, err := multiplexerOutputFn(projectPath, "tmux", "has-session", "-t", "="+name); err == nil {
+		return nil
+	}
+	args := []string{"tmux", "new-session", "-d", "-s", name, "-c", projectPath}
+	if layout != "" {
+		// A separate ";" argument chains commands in one tmux invocation,
+		// so the layout runs against the session just created.
+		args = append(args, ";", "source-file", layout)
+	}
+	if output, err := multiplexerOutputFn(projectPath, args...); err != nil {
+		return multiplexerError("create tmux session", output, err)
+	}
+	return nil
+}
+
+// openZellijSession attaches to a running session or starts a new one with
+// the layout. zellij cannot switch sessions from the command line, so this
+// refuses to nest inside an existing session.
+func openZellijSession(name, layout, projectPath string, in io.Reader, out, errOut io.Writer) error {
+	if os.Getenv("ZELLIJ") != "" {
+		return errors.New("already inside zellij: detach first, then run hatch again")
+	}
+	output, err := multiplexerOutputFn(projectPath, "zellij", "list-sessions", "--short", "--no-formatting")
+	if err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			if strings.TrimSpace(line) == name {
+				return multiplexerAttachFn(projectPath, in, out, errOut, "zellij", "attach", name)
+			}
+		}
+	}
+	args := []string{"zellij", "--session", name}
+	if layout != "" {
+		args = append(args, "--layout", layout)
+	}
+	return multiplexerAttachFn(projectPath, in, out, errOut, args...)
+}
+
+func multiplexerError(action string, output []byte, err error) error {
+	if msg := strings.TrimSpace(string(output)); msg != "" {
+		return fmt.Errorf("%s: %s", action, msg)
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+func runMultiplexerOutput(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	return cmd.CombinedOutput()
+}
+
+// runMultiplexerAttach runs a command that takes over the terminal until the
+// user detaches.
+func runMultiplexerAttach(dir string, in io.Reader, out, errOut io.Writer, args ...string) error {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = in, out, errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("attach %s session: %w", args[0], err)
+	}
+	return nil
+}
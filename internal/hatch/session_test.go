@@ -0,0 +1,170 @@
+package hatch
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSessionName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"/root/hatchery/2026-02-28-api":     "2026-02-28-api",
+		"/root/hatchery/2026-02-28-v1.2":    "2026-02-28-v1-2",
+		"/root/hatchery/2026-02-28-a:b.c_d": "2026-02-28-a-b-c_d",
+	}
+	for path, want := range tests {
+		if got := sessionName(path); got != want {
+			t.Fatalf("sessionName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestEnsureTmuxSessionAppliesLayout(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not available")
+	}
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	project := filepath.Join(t.TempDir(), "2026-02-28-api")
+	if err := os.MkdirAll(project, 0o755); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	layout := filepath.Join(t.TempDir(), "layout.tmux")
+	if err := os.WriteFile(layout, []byte("rename-window work\nsplit-window -h -c \"#{session_path}\"\n"), 0o644); err != nil {
+		t.Fatalf("write layout: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := ensureTmuxSession("2026-02-28-api", layout, project); err != nil {
+			t.Fatalf("ensureTmuxSession returned error: %v", err)
+		}
+	}
+
+	output, err := exec.Command("tmux", "list-panes", "-s", "-t", "=2026-02-28-api", "-F", "#{window_name} #{pane_current_path}").CombinedOutput()
+	if err != nil {
+		t.Fatalf("list panes: %v\n%s", err, output)
+	}
+	resolved, err := filepath.EvalSymlinks(project)
+	if err != nil {
+		t.Fatalf("resolve project: %v", err)
+	}
+	want := "work " + resolved + "\nwork " + resolved
+	if got := strings.TrimSpace(string(output)); got != want {
+		t.Fatalf("panes = %q, want %q", got, want)
+	}
+}
+
+func TestOpenSessionAttachesOrSwitches(t *testing.T) {
+	var calls [][]string
+	originalOutput, originalAttach := multiplexerOutputFn, multiplexerAttachFn
+	multiplexerOutputFn = func(_ string, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		if args[0] == "zellij" {
+			return []byte("other\n2026-02-28-api\n"), nil
+		}
+		return nil, nil
+	}
+	multiplexerAttachFn = func(_ string, _ io.Reader, _, _ io.Writer, args ...string) error {
+		calls = append(calls, append([]string{"attach:"}, args...))
+		return nil
+	}
+	t.Cleanup(func() { multiplexerOutputFn, multiplexerAttachFn = originalOutput, originalAttach })
+
+	project := "/root/hatchery/2026-02-28-api"
+	tests := []struct {
+		name    string
+		program multiplexer
+		env     map[string]string
+		want    [][]string
+	}{
+		{
+			name:    "tmux outside tmux",
+			program: multiplexerTmux,
+			env:     map[string]string{"TMUX": ""},
+			want: [][]string{
+				{"tmux", "has-session", "-t", "=2026-02-28-api"},
+				{"attach:", "tmux", "attach-session", "-t", "=2026-02-28-api"},
+			},
+		},
+		{
+			name:    "tmux inside tmux",
+			program: multiplexerTmux,
+			env:     map[string]string{"TMUX": "/tmp/tmux-0/default,1,0"},
+			want: [][]string{
+				{"tmux", "has-session", "-t", "=2026-02-28-api"},
+				{"tmux", "switch-client", "-t", "=2026-02-28-api"},
+			},
+		},
+		{
+			name:    "zellij existing session",
+			program: multiplexerZellij,
+			env:     map[string]string{"ZELLIJ": ""},
+			want: [][]string{
+				{"zellij", "list-sessions", "--short", "--no-formatting"},
+				{"attach:", "zellij", "attach", "2026-02-28-api"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			if err := openSession(tc.program, sessionConfig{}, project, nil, io.Discard, io.Discard); err != nil {
+				t.Fatalf("openSession returned error: %v", err)
+			}
+			if !reflect.DeepEqual(calls, tc.want) {
+				t.Fatalf("calls = %v, want %v", calls, tc.want)
+			}
+		})
+	}
+
+	t.Setenv("ZELLIJ", "0")
+	if err := openSession(multiplexerZellij, sessionConfig{}, project, nil, io.Discard, io.Discard); err == nil {
+		t.Fatalf("expected an error when already inside zellij")
+	}
+}
+
+func TestRunTmuxFlag(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	t.Setenv("TMUX", "")
+
+	var attached []string
+	originalOutput, originalAttach := multiplexerOutputFn, multiplexerAttachFn
+	multiplexerOutputFn = func(_ string, args ...string) ([]byte, error) {
+		if args[1] == "has-session" {
+			return []byte("can't find session"), exec.ErrNotFound
+		}
+		return nil, nil
+	}
+	multiplexerAttachFn = func(dir string, _ io.Reader, _, _ io.Writer, args ...string) error {
+		attached = append([]string{dir}, args...)
+		return nil
+	}
+	t.Cleanup(func() { multiplexerOutputFn, multiplexerAttachFn = originalOutput, originalAttach })
+
+	out := new(bytes.Buffer)
+	if err := run([]string{"--tmux", "api"}, strings.NewReader(""), out, io.Discard, fixedNow); err != nil {
+		t.Fatalf("run --tmux returned error: %v", err)
+	}
+	project := filepath.Join(root, "2026-02-28-api")
+	want := []string{project, "tmux", "attach-session", "-t", "=2026-02-28-api"}
+	if !reflect.DeepEqual(attached, want) {
+		t.Fatalf("attached %v, want %v", attached, want)
+	}
+
+	if err := run([]string{"--tmux", "--zellij", "api"}, strings.NewReader(""), out, io.Discard, fixedNow); err == nil {
+		t.Fatalf("expected --tmux with --zellij to fail")
+	}
+}
@@ -2,33 +2,78 @@ package hatch
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
+// hookShells are the shells --init supports. shellInit also accepts
+// "nushell" and "powershell".
+var hookShells = []string{"zsh", "bash", "fish", "nu", "pwsh", "elvish", "xonsh"}
+
+// hookArgs are the arguments every hook passes to hatch ahead of the user's
+// own, so hatch knows which protocol and shell to write actions for.
+func hookArgs(shell string) string {
+	return "--hook-version " + strconv.Itoa(hookProtocol) + " --hook-shell " + shell
+}
+
+// shellInit returns the cd hook for shell. zsh, bash, and fish get their
+// completions appended, so a single eval in the startup file sets up both.
 func shellInit(shell string) (string, error) {
+	var hook string
 	switch strings.ToLower(strings.TrimSpace(shell)) {
-	case "zsh", "bash":
-		return posixShellInit(), nil
+	case "zsh":
+		hook = posixShellInit("zsh")
+	case "bash":
+		hook = posixShellInit("bash")
 	case "fish":
-		return fishShellInit(), nil
+		hook = fishShellInit()
+	case "nu", "nushell":
+		return nushellInit(), nil
+	case "pwsh", "powershell":
+		return pwshInit(), nil
+	case "elvish":
+		return elvishInit(), nil
+	case "xonsh":
+		return xonshInit(), nil
 	default:
-		return "", fmt.Errorf("unsupported shell %q (use zsh, bash, or fish)", shell)
+		return "", fmt.Errorf("unsupported shell %q (use zsh, bash, fish, nu, pwsh, elvish, or xonsh)", shell)
 	}
+	completion, err := completionScript(shell)
+	if err != nil {
+		return "", err
+	}
+	return hook + "\n\n" + completion, nil
 }
 
-func posixShellInit() string {
+// The hooks below read the actions file written by shellActions: one action
+// per line, fields separated by tabs. Unknown actions are skipped so newer
+// binaries can add actions without breaking installed hooks.
+
+func posixShellInit(shell string) string {
 	return `hatch() {
-  local _hatch_cwd_file
+  local _hatch_cwd_file _hatch_line _hatch_rest _hatch_tab
   _hatch_cwd_file="$(mktemp "${TMPDIR:-/tmp}/hatch-cwd.XXXXXX")"
-  command hatch --cwd-file "$_hatch_cwd_file" "$@"
+  command hatch --cwd-file "$_hatch_cwd_file" ` + hookArgs(shell) + ` "$@"
   local _hatch_status=$?
 
   if [ $_hatch_status -eq 0 ] && [ -s "$_hatch_cwd_file" ]; then
-    local _hatch_target
-    _hatch_target="$(cat "$_hatch_cwd_file")"
-    if [ -d "$_hatch_target" ]; then
-      cd "$_hatch_target" || return $_hatch_status
-    fi
+    _hatch_tab="$(printf '\t')"
+    while IFS= read -r _hatch_line || [ -n "$_hatch_line" ]; do
+      _hatch_rest="${_hatch_line#*"$_hatch_tab"}"
+      case "$_hatch_line" in
+        "cd$_hatch_tab"*)
+          if [ -d "$_hatch_rest" ]; then
+            cd "$_hatch_rest" || _hatch_status=$?
+          fi
+          ;;
+        "set$_hatch_tab"*)
+          export "${_hatch_rest%%"$_hatch_tab"*}=${_hatch_rest#*"$_hatch_tab"}"
+          ;;
+        "source$_hatch_tab"*)
+          . "$_hatch_rest"
+          ;;
+      esac
+    done < "$_hatch_cwd_file"
   fi
 
   rm -f "$_hatch_cwd_file"
@@ -39,19 +84,164 @@ func posixShellInit() string {
 func fishShellInit() string {
 	return `function hatch
   set -l _hatch_cwd_file (mktemp (string join '' (or $TMPDIR /tmp) '/hatch-cwd.XXXXXX'))
-  command hatch --cwd-file "$_hatch_cwd_file" $argv
+  command hatch --cwd-file "$_hatch_cwd_file" ` + hookArgs("fish") + ` $argv
   set -l _hatch_status $status
 
   if test $_hatch_status -eq 0
-    if test -s "$_hatch_cwd_file"
-      set -l _hatch_target (cat "$_hatch_cwd_file")
-      if test -d "$_hatch_target"
-        cd "$_hatch_target"
+    while read -l _hatch_line
+      set -l _hatch_action (string split -m 1 \t -- $_hatch_line)
+      switch $_hatch_action[1]
+        case cd
+          if test -d "$_hatch_action[2]"
+            cd "$_hatch_action[2]"
+          end
+        case set
+          set -l _hatch_pair (string split -m 1 \t -- $_hatch_action[2])
+          if string match -q '*PATH' -- $_hatch_pair[1]
+            set -gx $_hatch_pair[1] (string split : -- $_hatch_pair[2])
+          else
+            set -gx $_hatch_pair[1] $_hatch_pair[2]
+          end
+        case source
+          source "$_hatch_action[2]"
       end
-    end
+    end < "$_hatch_cwd_file"
   end
 
   rm -f "$_hatch_cwd_file"
   return $_hatch_status
 end`
 }
+
+// nushellInit defines a --env command so cd reaches the caller. --wrapped
+// passes hatch's own flags through untouched. A failing hatch is caught so
+// the temp file is always removed, then re-raised before any action runs.
+// nushell can only source files known when the config is parsed, so source
+// actions are skipped.
+func nushellInit() string {
+	return `def --env --wrapped hatch [...args: string] {
+  let cwd_file = (mktemp --tmpdir hatch-cwd.XXXXXX)
+  let failure = (try { ^hatch --cwd-file $cwd_file ` + hookArgs("nu") + ` ...$args; null } catch {|err| $err })
+  let actions = (open --raw $cwd_file | lines | each {|line| $line | split row --number 2 (char tab) })
+  rm -f $cwd_file
+  if $failure != null {
+    error make --unspanned { msg: $"hatch failed: ($failure.msg)" }
+  }
+
+  let vars = ($actions | where {|action| $action.0 == "set" } | each {|action| $action.1 | split row --number 2 (char tab) })
+  load-env ($vars | reduce --fold {} {|pair, acc|
+    $acc | upsert $pair.0 (if ($pair.0 | str ends-with "PATH") { $pair.1 | split row (char esep) } else { $pair.1 })
+  })
+  let dirs = ($actions | where {|action| $action.0 == "cd" and (($action.1 | path type) == "dir") } | each {|action| $action.1 })
+  if ($dirs | is-not-empty) {
+    cd ($dirs | last)
+  }
+}`
+}
+
+func pwshInit() string {
+	return `function hatch {
+  $hatchCwdFile = [System.IO.Path]::GetTempFileName()
+  try {
+    $hatchCommand = Get-Command -Name hatch -CommandType Application | Select-Object -First 1
+    & $hatchCommand --cwd-file $hatchCwdFile ` + hookArgs("pwsh") + ` @args
+    if ($LASTEXITCODE -eq 0) {
+      foreach ($hatchLine in Get-Content -LiteralPath $hatchCwdFile) {
+        $hatchAction = $hatchLine -split [char]9, 2
+        switch ($hatchAction[0]) {
+          'cd' {
+            if (Test-Path -LiteralPath $hatchAction[1] -PathType Container) {
+              Set-Location -LiteralPath $hatchAction[1]
+            }
+          }
+          'set' {
+            $hatchPair = $hatchAction[1] -split [char]9, 2
+            Set-Item -LiteralPath "Env:$($hatchPair[0])" -Value $hatchPair[1]
+          }
+          'source' {
+            . $hatchAction[1]
+          }
+        }
+      }
+    }
+  } finally {
+    Remove-Item -Force -LiteralPath $hatchCwdFile -ErrorAction SilentlyContinue
+  }
+}`
+}
+
+// elvishInit ends with edit:add-var because functions defined by eval are
+// otherwise dropped when eval returns.
+func elvishInit() string {
+	return `use path
+use str
+
+fn hatch {|@args|
+  var tmpdir = /tmp
+  if (has-env TMPDIR) {
+    set tmpdir = (get-env TMPDIR)
+  }
+  var cwd-file = (e:mktemp $tmpdir/hatch-cwd.XXXXXX)
+  try {
+    e:hatch --cwd-file $cwd-file ` + hookArgs("elvish") + ` $@args
+    for line [(from-lines < $cwd-file)] {
+      var action = [(str:split &max=2 "\t" $line)]
+      if (==s $action[0] cd) {
+        if (path:is-dir $action[1]) {
+          cd $action[1]
+        }
+      } elif (==s $action[0] set) {
+        var pair = [(str:split &max=2 "\t" $action[1])]
+        set-env $pair[0] $pair[1]
+      } elif (==s $action[0] source) {
+        eval (slurp < $action[1])
+      }
+    }
+  } finally {
+    e:rm -f $cwd-file
+  }
+}
+
+edit:add-var hatch~ $hatch~`
+}
+
+// xonshInit registers an alias; unthreadable keeps the browser attached to
+// the terminal.
+func xonshInit() string {
+	return `import os as _hatch_os
+import shutil as _hatch_shutil
+import sys as _hatch_sys
+import tempfile as _hatch_tempfile
+from xonsh.dirstack import cd as _hatch_cd
+from xonsh.tools import unthreadable as _hatch_unthreadable
+
+
+@_hatch_unthreadable
+def _hatch(args):
+    binary = _hatch_shutil.which("hatch")
+    if binary is None:
+        print("hatch: command not found", file=_hatch_sys.stderr)
+        return 127
+    fd, cwd_file = _hatch_tempfile.mkstemp(prefix="hatch-cwd.")
+    _hatch_os.close(fd)
+    try:
+        status = ![@(binary) --cwd-file @(cwd_file) ` + hookArgs("xonsh") + ` @(args)].returncode
+        if status == 0:
+            with open(cwd_file) as f:
+                lines = f.read().splitlines()
+            for line in lines:
+                kind, _, rest = line.partition("\t")
+                if kind == "cd" and _hatch_os.path.isdir(rest):
+                    _hatch_cd([rest])
+                elif kind == "set":
+                    name, _, value = rest.partition("\t")
+                    __xonsh__.env[name] = value
+                elif kind == "source":
+                    aliases["source"]([rest])
+        return status
+    finally:
+        _hatch_os.remove(cwd_file)
+
+
+aliases["hatch"] = _hatch`
+}
@@ -0,0 +1,81 @@
+package hatch
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellInitUsesCWDFileProtocol(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range append(hookShells, "nushell", "powershell", " ZSH ") {
+		script, err := shellInit(shell)
+		if err != nil {
+			t.Fatalf("shellInit(%q) returned error: %v", shell, err)
+		}
+		if !strings.Contains(script, "--cwd-file") || !strings.Contains(script, "--hook-version 2 --hook-shell") {
+			t.Fatalf("shellInit(%q) does not speak the hook protocol:\n%s", shell, script)
+		}
+	}
+	if _, err := shellInit("tcsh"); err == nil || !strings.Contains(err.Error(), "xonsh") {
+		t.Fatalf("expected unsupported shell error listing shells, got %v", err)
+	}
+}
+
+func TestNushellHookCleansUpWhenHatchFails(t *testing.T) {
+	t.Parallel()
+
+	script := nushellInit()
+	call := strings.Index(script, "try { ^hatch")
+	cleanup := strings.Index(script, "rm -f $cwd_file")
+	raise := strings.Index(script, "error make")
+	if call < 0 || cleanup < call || raise < cleanup {
+		t.Fatalf("nushell hook must catch a failing hatch, remove the cwd file, then re-raise:\n%s", script)
+	}
+}
+
+// TestShellHooksParse checks each hook, with completions where bundled,
+// using the shell's own parser where that shell is installed.
+func TestShellHooksParse(t *testing.T) {
+	checks := map[string]func(path string) *exec.Cmd{
+		"bash": func(path string) *exec.Cmd { return exec.Command("bash", "-n", path) },
+		"zsh":  func(path string) *exec.Cmd { return exec.Command("zsh", "-n", path) },
+		"fish": func(path string) *exec.Cmd { return exec.Command("fish", "--no-execute", path) },
+		"nu": func(path string) *exec.Cmd {
+			return exec.Command("nu", "--no-config-file", "-c", "nu-check --debug '"+path+"'")
+		},
+		"pwsh": func(path string) *exec.Cmd {
+			script := "$errors = $null; [System.Management.Automation.Language.Parser]::ParseFile('" + path + "', [ref]$null, [ref]$errors) | Out-Null; if ($errors) { $errors; exit 1 }"
+			return exec.Command("pwsh", "-NoProfile", "-NonInteractive", "-Command", script)
+		},
+		"elvish": func(path string) *exec.Cmd { return exec.Command("elvish", "-norc", "-compileonly", path) },
+		"xonsh": func(path string) *exec.Cmd {
+			return exec.Command("xonsh", "--no-rc", "-c", "compilex(open('"+path+"').read())")
+		},
+	}
+	checked := 0
+	for shell, check := range checks {
+		cmd := check("")
+		if _, err := exec.LookPath(cmd.Args[0]); err != nil {
+			continue
+		}
+		script, err := shellInit(shell)
+		if err != nil {
+			t.Fatalf("shellInit(%s): %v", shell, err)
+		}
+		path := filepath.Join(t.TempDir(), "init."+shell)
+		if err := os.WriteFile(path, []byte(script+"\n"), 0o644); err != nil {
+			t.Fatalf("write script: %v", err)
+		}
+		if output, err := check(path).CombinedOutput(); err != nil {
+			t.Fatalf("%s rejects its init script: %v\n%s", shell, err, output)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Skip("no supported shells installed")
+	}
+}
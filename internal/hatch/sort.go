@@ -0,0 +1,123 @@
+package hatch
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sortMode is the browser's project order, cycled with Ctrl+S and kept in
+// the hatchery metadata between sessions.
+type sortMode string
+
+const (
+	// sortDate orders by the date prefix, newest first. While filtering it
+	// ranks by match quality instead.
+	sortDate     sortMode = "date"
+	sortModified sortMode = "modified"
+	sortSize     sortMode = "size"
+	sortName     sortMode = "name"
+	sortFrecency sortMode = "frecency"
+)
+
+var sortModes = []sortMode{sortDate, sortModified, sortSize, sortName, sortFrecency}
+
+func parseSortMode(value string) (sortMode, error) {
+	if value == "" {
+		return sortDate, nil
+	}
+	for _, mode := range sortModes {
+		if string(mode) == value {
+			return mode, nil
+		}
+	}
+	return sortDate, fmt.Errorf("unknown sort mode %q", value)
+}
+
+// next returns the mode after s in the Ctrl+S cycle.
+func (s sortMode) next() sortMode {
+	for i, mode := range sortModes {
+		if mode == s {
+			return sortModes[(i+1)%len(sortModes)]
+		}
+	}
+	return sortDate
+}
+
+// needsStats reports whether the order depends on walking the projects.
+func (s sortMode) needsStats() bool {
+	return s == sortModified || s == sortSize
+}
+
+func (s sortMode) label() string {
+	switch s {
+	case sortModified:
+		return "last modified"
+	case sortSize:
+		return "size"
+	case sortName:
+		return "name"
+	case sortFrecency:
+		return "frecency"
+	default:
+		return "date"
+	}
+}
+
+// compareProjects orders a before b (negative) for modes other than
+// sortDate, falling back to the date order on ties.
+func compareProjects(mode sortMode, a, b Project, metaA, metaB projectMeta, statsA, statsB projectStats, now time.Time) int {
+	var order int
+	switch mode {
+	case sortModified:
+		order = statsB.Modified.Compare(statsA.Modified)
+	case sortSize:
+		order = cmp.Compare(statsB.Size, statsA.Size)
+	case sortName:
+		order = strings.Compare(undatedName(a.Name), undatedName(b.Name))
+	case sortFrecency:
+		order = cmp.Compare(frecency(metaB, now), frecency(metaA, now))
+	}
+	if order != 0 {
+		return order
+	}
+	return strings.Compare(b.Name, a.Name)
+}
+
+func undatedName(name string) string {
+	if datedPrefix(name) != "" {
+		return name[11:]
+	}
+	return name
+}
+
+// frecency weighs how often a project was opened by how recently, the way
+// zoxide ranks directories.
+func frecency(meta projectMeta, now time.Time) float64 {
+	if meta.Visits == 0 {
+		return 0
+	}
+	age := now.Sub(meta.LastVisit)
+	switch {
+	case age < time.Hour:
+		return float64(meta.Visits) * 4
+	case age < 24*time.Hour:
+		return float64(meta.Visits) * 2
+	case age < 7*24*time.Hour:
+		return float64(meta.Visits) / 2
+	default:
+		return float64(meta.Visits) / 4
+	}
+}
+
+// recordVisit counts an entry into the project at path for frecency.
+func recordVisit(root, path string, now time.Time) error {
+	return updateMetadata(root, func(md *metadata) error {
+		meta := md.project(path)
+		meta.Visits++
+		meta.LastVisit = now
+		md.setProject(path, meta)
+		return nil
+	})
+}
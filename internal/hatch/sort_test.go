@@ -0,0 +1,104 @@
+package hatch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareProjects(t *testing.T) {
+	t.Parallel()
+
+	now := fixedNow()
+	projects := []Project{
+		{Name: "2026-02-28-zeta", Path: "/h/2026-02-28-zeta"},
+		{Name: "2026-01-01-alpha", Path: "/h/2026-01-01-alpha"},
+		{Name: "2026-02-01-mid", Path: "/h/2026-02-01-mid"},
+	}
+	meta := map[string]projectMeta{
+		"/h/2026-01-01-alpha": {Visits: 10, LastVisit: now.Add(-30 * 24 * time.Hour)},
+		"/h/2026-02-01-mid":   {Visits: 2, LastVisit: now.Add(-time.Minute)},
+	}
+	stats := map[string]projectStats{
+		"/h/2026-02-28-zeta":  {Size: 10, Modified: now.Add(-48 * time.Hour)},
+		"/h/2026-01-01-alpha": {Size: 300, Modified: now.Add(-time.Hour)},
+		"/h/2026-02-01-mid":   {Size: 20},
+	}
+
+	tests := map[sortMode]string{
+		sortModified: "alpha,zeta,mid",
+		sortSize:     "alpha,mid,zeta",
+		sortName:     "alpha,mid,zeta",
+		sortFrecency: "mid,alpha,zeta",
+	}
+	for mode, want := range tests {
+		ordered := append([]Project(nil), projects...)
+		sort.Slice(ordered, func(i, j int) bool {
+			a, b := ordered[i], ordered[j]
+			return compareProjects(mode, a, b, meta[a.Path], meta[b.Path], stats[a.Path], stats[b.Path], now) < 0
+		})
+		var names []string
+		for _, project := range ordered {
+			names = append(names, undatedName(project.Name))
+		}
+		if got := strings.Join(names, ","); got != want {
+			t.Fatalf("%s order = %s, want %s", mode, got, want)
+		}
+	}
+}
+
+func TestSortModeCycle(t *testing.T) {
+	t.Parallel()
+
+	mode := sortDate
+	var seen []string
+	for range sortModes {
+		mode = mode.next()
+		seen = append(seen, string(mode))
+	}
+	if got := strings.Join(seen, ","); got != "modified,size,name,frecency,date" {
+		t.Fatalf("cycle = %s", got)
+	}
+	if _, err := parseSortMode("random"); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
+
+func TestWalkProjectStats(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.txt": "hello", "nested/b.txt": "hi"})
+	newest := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(root, "nested", "b.txt"), newest, newest); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	stats := walkProjectStats(root)
+	if stats.Size != 7 || stats.Files != 2 || !stats.Modified.Equal(newest) {
+		t.Fatalf("stats = %+v, want 7 bytes in 2 files modified %s", stats, newest)
+	}
+}
+
+func TestRunRecordsVisits(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	writeTree(t, filepath.Join(root, "2026-01-15-api"), map[string]string{"keep": ""})
+
+	for range 2 {
+		if err := run([]string{"--on-exists", "open", "2026-01-15-api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("run returned error: %v", err)
+		}
+	}
+	md, err := loadMetadata(root)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta := md.project(filepath.Join(root, "2026-01-15-api")); meta.Visits != 2 || !meta.LastVisit.Equal(fixedNow()) {
+		t.Fatalf("visits = %+v, want 2 visits at %s", meta, fixedNow())
+	}
+}
@@ -0,0 +1,123 @@
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// space is a named hatchery root from config, such as "work" for
+// ~/work/hatchery.
+type space struct {
+	Name string
+	Root string
+}
+
+// spaceRoot returns the hatchery root to use: HATCHERY_HOME when set,
+// otherwise the named space, the configured default space, or ~/hatchery.
+func spaceRoot(cfg config, name string) (string, error) {
+	if strings.TrimSpace(os.Getenv("HATCHERY_HOME")) != "" {
+		return hatcheryRoot()
+	}
+	if name == "" {
+		name = cfg.Space
+	}
+	if name == "" {
+		return hatcheryRoot()
+	}
+	root, ok := cfg.Spaces[name]
+	if !ok {
+		if len(cfg.Spaces) == 0 {
+			return "", fmt.Errorf("unknown space %q (no spaces configured)", name)
+		}
+		return "", fmt.Errorf("unknown space %q (use %s)", name, strings.Join(spaceNames(cfg), ", "))
+	}
+	return expandPath(root)
+}
+
+// spaceNames lists the configured space names in order.
+func spaceNames(cfg config) []string {
+	names := make([]string, 0, len(cfg.Spaces))
+	for name := range cfg.Spaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// browserSpaces lists the spaces the browser cycles through, by name, with
+// root included as "default" when no space points at it. It returns nil
+// when there is only one place to look, including whenever HATCHERY_HOME
+// pins the root.
+func browserSpaces(cfg config, root string) []space {
+	if strings.TrimSpace(os.Getenv("HATCHERY_HOME")) != "" || len(cfg.Spaces) == 0 {
+		return nil
+	}
+	var spaces []space
+	found := false
+	for _, name := range spaceNames(cfg) {
+		spaceRoot, err := expandPath(cfg.Spaces[name])
+		if err != nil {
+			continue
+		}
+		found = found || filepath.Clean(spaceRoot) == filepath.Clean(root)
+		spaces = append(spaces, space{Name: name, Root: spaceRoot})
+	}
+	if !found {
+		spaces = append([]space{{Name: "default", Root: root}}, spaces...)
+	}
+	if len(spaces) < 2 {
+		return nil
+	}
+	return spaces
+}
+
+// resolveProject finds ref in the current space first. When nothing there
+// matches, it looks in the other configured spaces: a path, including the
+// working directory when ref is empty, finds the project it lies in, and a
+// name must match in exactly one of them, so commands run from anywhere
+// find a project in any space. Its root is filepath.Dir of its path.
+func (c commandContext) resolveProject(ref string) (Project, error) {
+	project, err := resolveProject(c.root, ref)
+	if err == nil || !errors.Is(err, errProjectNotFound) {
+		return project, err
+	}
+	var found []Project
+	var names []string
+	for _, space := range browserSpaces(c.cfg, c.root) {
+		if filepath.Clean(space.Root) == filepath.Clean(c.root) {
+			continue
+		}
+		// Looking never creates a space root that does not exist yet.
+		if _, statErr := os.Stat(space.Root); statErr != nil {
+			continue
+		}
+		if other, otherErr := resolveProject(space.Root, ref); otherErr == nil {
+			found = append(found, other)
+			names = append(names, space.Name)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return project, err
+	case 1:
+		return found[0], nil
+	default:
+		return Project{}, fmt.Errorf("%s is in more than one space (%s); pick one with --space", ref, strings.Join(names, ", "))
+	}
+}
+
+// isSpaceRoot reports whether dir is the root of a space the browser can
+// show, so entering a project there from the all-spaces view still counts
+// as a visit.
+func (c commandContext) isSpaceRoot(dir string) bool {
+	for _, space := range browserSpaces(c.cfg, c.root) {
+		if filepath.Clean(space.Root) == dir {
+			return true
+		}
+	}
+	return false
+}
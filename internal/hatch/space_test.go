@@ -0,0 +1,250 @@
+package hatch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSpaceRoot(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("HATCHERY_HOME", "")
+	cfg := config{Spaces: map[string]string{"work": "~/work/hatchery", "oss": filepath.Join(home, "oss")}}
+
+	tests := []struct {
+		cfg  config
+		name string
+		want string
+	}{
+		{cfg: cfg, want: filepath.Join(home, "hatchery")},
+		{cfg: cfg, name: "work", want: filepath.Join(home, "work", "hatchery")},
+		{cfg: config{Spaces: cfg.Spaces, Space: "oss"}, want: filepath.Join(home, "oss")},
+		{cfg: config{Spaces: cfg.Spaces, Space: "oss"}, name: "work", want: filepath.Join(home, "work", "hatchery")},
+	}
+	for _, tt := range tests {
+		if got, err := spaceRoot(tt.cfg, tt.name); err != nil || got != tt.want {
+			t.Fatalf("spaceRoot(%q) = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := spaceRoot(cfg, "home"); err == nil || !strings.Contains(err.Error(), "use oss, work") {
+		t.Fatalf("expected unknown space error, got %v", err)
+	}
+
+	override := filepath.Join(home, "elsewhere")
+	t.Setenv("HATCHERY_HOME", override)
+	if got, err := spaceRoot(cfg, "work"); err != nil || got != override {
+		t.Fatalf("HATCHERY_HOME should win, got %q, %v", got, err)
+	}
+	if spaces := browserSpaces(cfg, override); spaces != nil {
+		t.Fatalf("expected no spaces under HATCHERY_HOME, got %v", spaces)
+	}
+}
+
+func TestBrowserSpaces(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HATCHERY_HOME", "")
+	work, oss := filepath.Join(home, "work"), filepath.Join(home, "oss")
+	cfg := config{Spaces: map[string]string{"work": work, "oss": oss}}
+
+	want := []space{{Name: "default", Root: filepath.Join(home, "hatchery")}, {Name: "oss", Root: oss}, {Name: "work", Root: work}}
+	if got := browserSpaces(cfg, filepath.Join(home, "hatchery")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("browserSpaces = %v, want %v", got, want)
+	}
+	if got := browserSpaces(cfg, work); !reflect.DeepEqual(got, want[1:]) {
+		t.Fatalf("browserSpaces = %v, want %v", got, want[1:])
+	}
+	if got := browserSpaces(config{Spaces: map[string]string{"work": work}}, work); got != nil {
+		t.Fatalf("expected a single space to need no cycling, got %v", got)
+	}
+}
+
+func TestRunCreatesProjectInSpace(t *testing.T) {
+	t.Setenv("HATCHERY_HOME", "")
+	work := filepath.Join(t.TempDir(), "work")
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"spaces": {"work": "`+filepath.ToSlash(work)+`"}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+
+	if err := run([]string{"--space", "work", "api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("run returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(work, "2026-02-28-api")); err != nil {
+		t.Fatalf("expected project in the work space: %v", err)
+	}
+	if err := run([]string{"--space", "play", "api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil {
+		t.Fatal("expected error for an unknown space")
+	}
+}
+
+func TestRunResolvesWorkingDirectoryInAnySpace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("HATCHERY_HOME", "")
+	work := filepath.Join(home, "work")
+	project := filepath.Join(work, "2026-02-28-api")
+	writeTree(t, project, map[string]string{"src/main.go": ""})
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"spaces": {"work": "`+filepath.ToSlash(work)+`"}}`), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+	t.Chdir(filepath.Join(project, "src"))
+
+	if err := run([]string{"pin"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err != nil {
+		t.Fatalf("pin returned error: %v", err)
+	}
+	md, err := loadMetadata(work)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if !md.project(project).Pinned {
+		t.Fatal("expected the project in the work space to be pinned in that space's metadata")
+	}
+}
+
+func TestRunResolvesNamesInAnySpace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("HATCHERY_HOME", "")
+	work, oss := filepath.Join(home, "work"), filepath.Join(home, "oss")
+	writeTree(t, filepath.Join(work, "2026-02-28-api"), map[string]string{"main.go": ""})
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	spaces := `{"spaces": {"work": "` + filepath.ToSlash(work) + `", "oss": "` + filepath.ToSlash(oss) + `"}}`
+	if err := os.WriteFile(configPath, []byte(spaces), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("HATCH_CONFIG", configPath)
+	t.Chdir(home)
+	pin := func() error {
+		return run([]string{"pin", "api"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow)
+	}
+
+	if err := pin(); err != nil {
+		t.Fatalf("pin returned error: %v", err)
+	}
+	md, err := loadMetadata(work)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if !md.project(filepath.Join(work, "2026-02-28-api")).Pinned {
+		t.Fatal("expected the project named in the work space to be pinned there")
+	}
+	if _, err := os.Stat(oss); !os.IsNotExist(err) {
+		t.Fatalf("expected looking in the oss space not to create it, stat err = %v", err)
+	}
+
+	writeTree(t, filepath.Join(oss, "2026-03-01-api"), map[string]string{"main.go": ""})
+	if err := pin(); err == nil || !strings.Contains(err.Error(), "more than one space (oss, work)") {
+		t.Fatalf("pin error = %v, want the name to be ambiguous", err)
+	}
+
+	current := filepath.Join(home, "hatchery")
+	writeTree(t, filepath.Join(current, "2026-03-02-api"), map[string]string{"main.go": ""})
+	if err := pin(); err != nil {
+		t.Fatalf("expected the current space to win, got %v", err)
+	}
+	if md, err := loadMetadata(current); err != nil || !md.project(filepath.Join(current, "2026-03-02-api")).Pinned {
+		t.Fatalf("expected the project in the current space to be pinned, got %v", err)
+	}
+}
+
+func TestCompleteArgsSpace(t *testing.T) {
+	t.Setenv("HATCHERY_HOME", "")
+	root, work := t.TempDir(), t.TempDir()
+	for _, dir := range []string{filepath.Join(root, "2026-02-27-home"), filepath.Join(work, "2026-02-28-api")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create %s: %v", dir, err)
+		}
+	}
+	cfg := config{Spaces: map[string]string{"work": work, "oss": t.TempDir()}}
+
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"--space", ""}, []string{":words", "oss", "work"}},
+		{[]string{"--space=w"}, []string{":words", "--space=work"}},
+		{[]string{"--space", "work", "2026"}, []string{":dirs", "2026-02-28-api"}},
+		{[]string{"--space=work", "pin", ""}, []string{":words", "2026-02-28-api"}},
+		{[]string{"2026"}, []string{":dirs", "2026-02-27-home"}},
+	}
+	for _, tt := range tests {
+		if got := completeArgs(root, cfg, tt.words); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("completeArgs(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
+
+func TestBrowserCyclesSpaces(t *testing.T) {
+	t.Parallel()
+
+	home := t.TempDir()
+	personal, work := filepath.Join(home, "hatchery"), filepath.Join(home, "work")
+	writeTree(t, filepath.Join(personal, "2026-02-27-blog"), map[string]string{"a": ""})
+	writeTree(t, filepath.Join(work, "2026-02-28-api"), map[string]string{"a": ""})
+	projects, err := listProjects(personal)
+	if err != nil {
+		t.Fatalf("listProjects: %v", err)
+	}
+
+	oss := filepath.Join(home, "oss")
+	model := newBrowserModel(personal, projects)
+	model.spaces = []space{{Name: "default", Root: personal}, {Name: "work", Root: work}, {Name: "oss", Root: oss}}
+	if view := model.View(); !strings.Contains(view, "space: default") || strings.Contains(view, "2026-02-28-api") {
+		t.Fatalf("expected only the default space:\n%s", view)
+	}
+
+	press := func() {
+		t.Helper()
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+		model = updated.(browserModel)
+	}
+	press()
+	if view := model.View(); !strings.Contains(view, "space: work") || !strings.Contains(view, "2026-02-28-api") || strings.Contains(view, "2026-02-27-blog") {
+		t.Fatalf("expected the work space:\n%s", view)
+	}
+
+	press()
+	if _, err := os.Stat(oss); !os.IsNotExist(err) || !strings.Contains(model.View(), "space: oss") {
+		t.Fatalf("expected an empty oss space that cycling does not create, stat err = %v:\n%s", err, model.View())
+	}
+
+	press()
+	view := model.View()
+	if !strings.Contains(view, "space: all spaces") || !strings.Contains(view, "work     2026-02-28-api") || !strings.Contains(view, "default  2026-02-27-blog") {
+		t.Fatalf("expected all spaces with a prefix column:\n%s", view)
+	}
+
+	// There is no one space to create a typed project in.
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("zzz")})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := updated.(browserModel).View(); strings.Contains(view, "Create New") || !strings.Contains(view, createInSpaceHint) {
+		t.Fatalf("expected no create row in all spaces:\n%s", view)
+	}
+
+	// Actions on a project go to its own space's metadata.
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = updated.(browserModel)
+	for root, want := range map[string]bool{work: true, personal: false} {
+		md, err := loadMetadata(root)
+		if err != nil {
+			t.Fatalf("load metadata: %v", err)
+		}
+		if got := md.project(filepath.Join(work, "2026-02-28-api")).Pinned; got != want {
+			t.Fatalf("pinned in %s = %v, want %v", root, got, want)
+		}
+	}
+
+	press()
+	if view := model.View(); !strings.Contains(view, "space: default") || strings.Contains(view, "2026-02-28-api") {
+		t.Fatalf("expected to cycle back to the default space:\n%s", view)
+	}
+}
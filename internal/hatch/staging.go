@@ -0,0 +1,148 @@
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateDirName holds hatch's own bookkeeping inside the hatchery root. It
+// starts with a dot so listProjects never shows it as a project.
+const stateDirName = ".hatch"
+
+// staleStagingAge is how old a staged directory must be before another run
+// treats it as left behind by a crashed or killed hatch.
+const staleStagingAge = 24 * time.Hour
+
+func stagingDir(root string) string {
+	return filepath.Join(root, stateDirName, "staging")
+}
+
+// stageProject builds a new project at a temporary path inside the hatchery
+// root and renames it to target only once build succeeds, so target is never
+// seen half-populated. build receives a path that does not exist yet and
+// must create it. On failure the staged files are removed. It returns the
+// final path, which differs from target only under existsSuffix.
+func stageProject(root, target string, onExists existsPolicy, build func(staged string) error) (string, error) {
+	if onExists != existsSuffix {
+		if _, err := os.Stat(target); err == nil {
+			return "", &projectExistsError{Path: target}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("check project directory: %w", err)
+		}
+	}
+
+	removeStaleStaging(root, time.Now())
+	if err := os.MkdirAll(stagingDir(root), 0o755); err != nil {
+		return "", fmt.Errorf("create staging directory: %w", err)
+	}
+	// The random parent keeps concurrent runs apart while the staged
+	// directory itself keeps the final name, so tools such as git clone
+	// report something recognizable.
+	parent, err := os.MkdirTemp(stagingDir(root), filepath.Base(target)+"-")
+	if err != nil {
+		return "", fmt.Errorf("create staging directory: %w", err)
+	}
+	defer removeStaged(parent)
+
+	staged := filepath.Join(parent, filepath.Base(target))
+	if err := build(staged); err != nil {
+		return "", err
+	}
+	err = withHatcheryLock(root, func() error {
+		if onExists == existsSuffix {
+			target = nextAvailablePath(target)
+		} else if _, err := os.Stat(target); err == nil {
+			return &projectExistsError{Path: target}
+		}
+		return renameStaged(staged, target)
+	})
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// claimProjectDir creates target as an empty directory under the hatchery
+// lock, for tools such as git worktree add that must populate the final path
+// in place. It returns the claimed path, suffixed under existsSuffix.
+func claimProjectDir(root, target string, onExists existsPolicy) (string, error) {
+	err := withHatcheryLock(root, func() error {
+		if onExists == existsSuffix {
+			target = nextAvailablePath(target)
+		}
+		if err := os.Mkdir(target, 0o755); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return &projectExistsError{Path: target}
+			}
+			return fmt.Errorf("create project directory: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// removeStaleStaging deletes staged directories that a previous run never
+// moved into place or cleaned up. Failures are ignored; the next run tries
+// again.
+func removeStaleStaging(root string, now time.Time) {
+	entries, err := os.ReadDir(stagingDir(root))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < staleStagingAge {
+			continue
+		}
+		_ = removeStaged(filepath.Join(stagingDir(root), entry.Name()))
+	}
+}
+
+// renameStaged moves a staged project into place. Moving a directory to a
+// new parent needs write permission on it, which a faithful copy of a
+// read-only source lacks, so the permission is lent for the rename and
+// taken back afterwards.
+func renameStaged(staged, target string) error {
+	info, err := os.Lstat(staged)
+	if err != nil {
+		return fmt.Errorf("move project into place: %w", err)
+	}
+	mode := info.Mode().Perm()
+	if mode&0o200 == 0 {
+		if err := os.Chmod(staged, mode|0o200); err != nil {
+			return fmt.Errorf("move project into place: %w", err)
+		}
+	}
+	if err := os.Rename(staged, target); err != nil {
+		_ = os.Chmod(staged, mode)
+		return fmt.Errorf("move project into place: %w", err)
+	}
+	if mode&0o200 == 0 {
+		if err := os.Chmod(target, mode); err != nil {
+			return fmt.Errorf("restore project permissions: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeStaged deletes a staged tree, first making its directories
+// writable so read-only ones copied from the source cannot block removal.
+func removeStaged(path string) error {
+	_ = filepath.WalkDir(path, func(entryPath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().Perm()&0o200 == 0 {
+			_ = os.Chmod(entryPath, info.Mode().Perm()|0o200)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
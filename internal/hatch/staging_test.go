@@ -0,0 +1,139 @@
+package hatch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCloneProjectRollsBackFailedClone(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	target := filepath.Join(root, "2026-02-28-hatch")
+
+	originalClone := gitCloneFn
+	fail := true
+	gitCloneFn = func(_ context.Context, _, staged string, _ io.Writer) ([]byte, error) {
+		if staged == target {
+			t.Fatalf("expected clone into a staging directory, got the final target")
+		}
+		if err := os.MkdirAll(filepath.Join(staged, ".git"), 0o755); err != nil {
+			t.Fatalf("create partial clone: %v", err)
+		}
+		if fail {
+			return []byte("fatal: early EOF"), errors.New("exit status 128")
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { gitCloneFn = originalClone })
+
+	if _, err := cloneProject(context.Background(), root, "https://github.com/nayeemzen/hatch.git", fixedNow(), existsFail, nil); err == nil {
+		t.Fatalf("expected clone failure")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected no project after failed clone, err=%v", err)
+	}
+	assertStagingEmpty(t, root)
+
+	fail = false
+	got, err := cloneProject(context.Background(), root, "https://github.com/nayeemzen/hatch.git", fixedNow(), existsFail, nil)
+	if err != nil {
+		t.Fatalf("retry returned error: %v", err)
+	}
+	if got != target {
+		t.Fatalf("retry path = %q, want %q", got, target)
+	}
+	if _, err := os.Stat(filepath.Join(target, ".git")); err != nil {
+		t.Fatalf("expected staged clone to be moved into place: %v", err)
+	}
+	assertStagingEmpty(t, root)
+}
+
+func TestCopyProjectRollsBackCancelledCopy(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	source := filepath.Join(t.TempDir(), "source")
+	writeTree(t, source, map[string]string{"a.txt": "a", "b/c.txt": "c"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := copyProject(ctx, root, source, "snapshot", fixedNow(), existsFail, copyOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("copyProject error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "2026-02-28-snapshot")); !os.IsNotExist(err) {
+		t.Fatalf("expected no project after cancelled copy, err=%v", err)
+	}
+	assertStagingEmpty(t, root)
+}
+
+func TestStageProjectRemovesStaleLeftovers(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	stale := filepath.Join(stagingDir(root), "2026-01-01-crashed-123")
+	fresh := filepath.Join(stagingDir(root), "2026-02-28-running-456")
+	for _, dir := range []string{stale, fresh} {
+		if err := os.MkdirAll(filepath.Join(dir, "partial"), 0o755); err != nil {
+			t.Fatalf("create leftover: %v", err)
+		}
+	}
+	old := time.Now().Add(-2 * staleStagingAge)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatalf("age leftover: %v", err)
+	}
+
+	if _, err := createProject(root, "next", fixedNow(), existsFail); err != nil {
+		t.Fatalf("createProject returned error: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected stale staging directory to be removed, err=%v", err)
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Fatalf("expected recent staging directory to be kept: %v", err)
+	}
+}
+
+func TestStageProjectHandlesReadOnlyDirectories(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	build := func(fail bool) func(string) error {
+		return func(staged string) error {
+			if err := os.MkdirAll(filepath.Join(staged, "vendor"), 0o755); err != nil {
+				return err
+			}
+			for _, dir := range []string{filepath.Join(staged, "vendor"), staged} {
+				if err := os.Chmod(dir, 0o555); err != nil {
+					return err
+				}
+			}
+			if fail {
+				return errors.New("build failed")
+			}
+			return nil
+		}
+	}
+
+	target, err := stageProject(root, filepath.Join(root, "2026-02-28-ro"), existsFail, build(false))
+	if err != nil {
+		t.Fatalf("stageProject returned error: %v", err)
+	}
+	t.Cleanup(func() { _ = removeStaged(target) })
+	if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0o555 {
+		t.Fatalf("expected read-only project in place, got %v, %v", info, err)
+	}
+
+	if _, err := stageProject(root, filepath.Join(root, "2026-02-28-broken"), existsFail, build(true)); err == nil {
+		t.Fatal("expected build error")
+	}
+	assertStagingEmpty(t, root)
+}
+
+func assertStagingEmpty(t *testing.T, root string) {
+	t.Helper()
+	entries, err := os.ReadDir(stagingDir(root))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("read staging directory: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected empty staging directory, found %d entries", len(entries))
+	}
+}
@@ -0,0 +1,220 @@
+package hatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// projectStats summarises what is inside a project directory.
+type projectStats struct {
+	// Size is the apparent size of all regular files, in bytes. Files
+	// hardlinked into several projects, as --copy-strategy hardlink does,
+	// count in each of them.
+	Size int64 `json:"size"`
+	// Files counts regular files.
+	Files int `json:"files"`
+	// Modified is the newest modification time of anything in the project,
+	// including the directory itself.
+	Modified time.Time `json:"modified"`
+	// Subdirs are the project's top-level directories, largest first.
+	Subdirs []dirSize `json:"subdirs,omitempty"`
+}
+
+type dirSize struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+var projectStatsFn = walkProjectStats
+
+// walkProjectStats walks path without following symlinks below it; a
+// symlinked project is walked at its target, which WalkDir would not enter.
+// Unreadable entries are skipped rather than failing the whole walk.
+func walkProjectStats(path string) projectStats {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		path = linkTarget(path)
+	}
+	var stats projectStats
+	subdirs := make(map[string]int64)
+	_ = filepath.WalkDir(path, func(entryPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(stats.Modified) {
+			stats.Modified = info.ModTime()
+		}
+		rel, _ := filepath.Rel(path, entryPath)
+		top, _, nested := strings.Cut(rel, string(filepath.Separator))
+		if _, seen := subdirs[top]; d.IsDir() && rel != "." && !nested && !seen {
+			subdirs[top] = 0
+		}
+		if info.Mode().IsRegular() {
+			stats.Size += info.Size()
+			stats.Files++
+			if nested {
+				subdirs[top] += info.Size()
+			}
+		}
+		return nil
+	})
+	for name, size := range subdirs {
+		stats.Subdirs = append(stats.Subdirs, dirSize{Name: name, Size: size})
+	}
+	sort.Slice(stats.Subdirs, func(i, j int) bool {
+		if stats.Subdirs[i].Size == stats.Subdirs[j].Size {
+			return stats.Subdirs[i].Name < stats.Subdirs[j].Name
+		}
+		return stats.Subdirs[i].Size > stats.Subdirs[j].Size
+	})
+	return stats
+}
+
+// statsCacheEntry is a project's stats as of the project directory's mtime.
+type statsCacheEntry struct {
+	DirModified time.Time `json:"dir_modified"`
+	// Scanned is when the project was walked.
+	Scanned time.Time `json:"scanned"`
+	projectStats
+}
+
+// statsCacheTTL bounds how long a cached entry is trusted, since edits
+// below the top level, such as npm install filling node_modules, leave the
+// project directory's mtime alone.
+var statsCacheTTL = time.Hour
+
+func statsCachePath(root string) string {
+	return filepath.Join(root, stateDirName, "sizes.json")
+}
+
+// scanProjects walks paths concurrently and returns their stats keyed by
+// path. With cached set, a project whose directory mtime matches the cache
+// in <root>/.hatch/sizes.json is not walked again until the entry is
+// statsCacheTTL old. That mtime only changes when top-level entries do, so
+// cached stats can lag behind edits deeper in the tree until then; callers
+// that need fresh modification times pass false. Walked projects always
+// refresh the cache.
+func scanProjects(root string, paths []string, cached bool) map[string]projectStats {
+	cache := loadStatsCache(root)
+	now := time.Now()
+	results := make(map[string]projectStats, len(paths))
+	dirModified := make(map[string]time.Time, len(paths))
+	var walk []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		dirModified[path] = info.ModTime()
+		if entry, ok := cache[filepath.Base(path)]; cached && ok && entry.DirModified.Equal(info.ModTime()) && now.Sub(entry.Scanned) < statsCacheTTL {
+			results[path] = entry.projectStats
+			continue
+		}
+		walk = append(walk, path)
+	}
+	if len(walk) == 0 {
+		return results
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for range min(len(walk), max(4, runtime.NumCPU())) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				stats := projectStatsFn(path)
+				mu.Lock()
+				results[path] = stats
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, path := range walk {
+		jobs <- path
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, path := range walk {
+		cache[filepath.Base(path)] = statsCacheEntry{DirModified: dirModified[path], Scanned: now, projectStats: results[path]}
+	}
+	_ = saveStatsCache(root, cache)
+	return results
+}
+
+func loadStatsCache(root string) map[string]statsCacheEntry {
+	cache := make(map[string]statsCacheEntry)
+	data, err := os.ReadFile(statsCachePath(root))
+	if err != nil || json.Unmarshal(data, &cache) != nil {
+		return make(map[string]statsCacheEntry)
+	}
+	return cache
+}
+
+// saveStatsCache writes the cache atomically. It is only a cache, so
+// concurrent writers may drop each other's entries.
+func saveStatsCache(root string, cache map[string]statsCacheEntry) error {
+	for name := range cache {
+		if _, err := os.Stat(filepath.Join(root, name)); err != nil {
+			delete(cache, name)
+		}
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("encode size cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(statsCachePath(root)), 0o755); err != nil {
+		return fmt.Errorf("write size cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(statsCachePath(root)), "sizes-*.json")
+	if err != nil {
+		return fmt.Errorf("write size cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write size cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write size cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), statsCachePath(root)); err != nil {
+		return fmt.Errorf("write size cache: %w", err)
+	}
+	return nil
+}
+
+// formatAge renders how long ago t was, such as "5m ago" or "3d ago".
+func formatAge(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case t.IsZero():
+		return ""
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	case age < 60*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	default:
+		return fmt.Sprintf("%dmo ago", int(age/(30*24*time.Hour)))
+	}
+}
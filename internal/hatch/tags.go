@@ -0,0 +1,145 @@
+package hatch
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// normalizeTag turns "#Customer X" or "+auth" into a tag such as
+// "customer-x". It reports false when nothing usable is left.
+func normalizeTag(value string) (string, bool) {
+	value = strings.TrimLeft(strings.TrimSpace(value), "#+")
+	value = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(value), "-"), "-.")
+	return value, value != ""
+}
+
+// editTags applies edits such as "+auth", "-spike", or "auth" (same as
+// "+auth") to tags and returns the sorted result.
+func editTags(tags []string, edits []string) ([]string, error) {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	for _, edit := range edits {
+		remove := strings.HasPrefix(edit, "-")
+		tag, ok := normalizeTag(strings.TrimPrefix(edit, "-"))
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q", edit)
+		}
+		set[tag] = !remove
+	}
+	return sortedTags(set), nil
+}
+
+// parseTags reads a space- or comma-separated list of tags, as typed in the
+// browser.
+func parseTags(input string) []string {
+	set := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if tag, ok := normalizeTag(field); ok {
+			set[tag] = true
+		}
+	}
+	return sortedTags(set)
+}
+
+func sortedTags(set map[string]bool) []string {
+	var tags []string
+	for tag, keep := range set {
+		if keep {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func formatTags(tags []string) string {
+	chips := make([]string, len(tags))
+	for i, tag := range tags {
+		chips[i] = "#" + tag
+	}
+	return strings.Join(chips, " ")
+}
+
+// setProjectTags replaces the tags of the project at path.
+func setProjectTags(root, path string, tags []string) error {
+	return updateMetadata(root, func(md *metadata) error {
+		meta := md.project(path)
+		meta.Tags = tags
+		md.setProject(path, meta)
+		return nil
+	})
+}
+
+// setProjectNote replaces the note of the project at path; an empty note
+// removes it.
+func setProjectNote(root, path, note string) error {
+	return updateMetadata(root, func(md *metadata) error {
+		meta := md.project(path)
+		meta.Note = strings.TrimSpace(note)
+		md.setProject(path, meta)
+		return nil
+	})
+}
+
+// runTag prints or edits a project's tags: hatch tag <project> +auth -spike.
+func runTag(c commandContext, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: hatch tag <project> [+tag|-tag ...]")
+	}
+	project, err := c.resolveProject(args[0])
+	if err != nil {
+		return err
+	}
+	var tags []string
+	err = updateMetadata(filepath.Dir(project.Path), func(md *metadata) error {
+		meta := md.project(project.Path)
+		edited, err := editTags(meta.Tags, args[1:])
+		if err != nil {
+			return err
+		}
+		meta.Tags, tags = edited, edited
+		md.setProject(project.Path, meta)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		fmt.Fprintln(c.out, project.Name+": no tags")
+		return nil
+	}
+	fmt.Fprintln(c.out, project.Name+": "+formatTags(tags))
+	return nil
+}
+
+// runNote prints or replaces a project's note: hatch note <project> "text".
+// An empty text removes the note.
+func runNote(c commandContext, args []string) error {
+	if len(args) == 0 {
+		return errors.New(`usage: hatch note <project> ["text"]`)
+	}
+	project, err := c.resolveProject(args[0])
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		md, err := loadMetadata(filepath.Dir(project.Path))
+		if err != nil {
+			return err
+		}
+		if note := md.project(project.Path).Note; note != "" {
+			fmt.Fprintln(c.out, note)
+		}
+		return nil
+	}
+	if err := setProjectNote(filepath.Dir(project.Path), project.Path, strings.Join(args[1:], " ")); err != nil {
+		return err
+	}
+	fmt.Fprintln(c.out, successStyle().Render("Noted "+project.Name))
+	return nil
+}
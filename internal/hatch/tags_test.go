@@ -0,0 +1,85 @@
+package hatch
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"auth":          "auth",
+		"#Customer X":   "customer-x",
+		"+spike":        "spike",
+		"  v1.2 ":       "v1.2",
+		"#":             "",
+		"+!!":           "",
+		"team/platform": "team-platform",
+	}
+	for input, want := range tests {
+		got, ok := normalizeTag(input)
+		if got != want || ok != (want != "") {
+			t.Fatalf("normalizeTag(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	t.Parallel()
+
+	got, err := editTags([]string{"old", "spike"}, []string{"+auth", "-spike", "Customer-X", "-missing"})
+	if err != nil {
+		t.Fatalf("editTags returned error: %v", err)
+	}
+	if want := []string{"auth", "customer-x", "old"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("editTags = %v, want %v", got, want)
+	}
+	if _, err := editTags(nil, []string{"+"}); err == nil {
+		t.Fatal("expected error for an empty tag")
+	}
+	if got := parseTags("#auth, spike  auth"); !reflect.DeepEqual(got, []string{"auth", "spike"}) {
+		t.Fatalf("parseTags = %v", got)
+	}
+}
+
+func TestRunTagAndNote(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	t.Setenv("HATCHERY_HOME", root)
+	writeTree(t, filepath.Join(root, "2026-02-28-api"), map[string]string{"keep": ""})
+
+	hatch := func(args ...string) string {
+		t.Helper()
+		out := new(bytes.Buffer)
+		if err := run(args, strings.NewReader(""), out, new(bytes.Buffer), fixedNow); err != nil {
+			t.Fatalf("hatch %v returned error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	if got := hatch("tag", "api", "+auth", "+spike"); got != "2026-02-28-api: #auth #spike\n" {
+		t.Fatalf("tag output = %q", got)
+	}
+	if got := hatch("tag", "api", "-spike"); got != "2026-02-28-api: #auth\n" {
+		t.Fatalf("tag output = %q", got)
+	}
+	if got := hatch("tag", "2026-02-28-api"); got != "2026-02-28-api: #auth\n" {
+		t.Fatalf("tag listing = %q", got)
+	}
+
+	hatch("note", "api", "why this exists")
+	if got := hatch("note", "api"); got != "why this exists\n" {
+		t.Fatalf("note output = %q", got)
+	}
+	hatch("note", "api", "")
+	if got := hatch("note", "api"); got != "" {
+		t.Fatalf("expected cleared note, got %q", got)
+	}
+
+	if err := run([]string{"tag"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer), fixedNow); err == nil {
+		t.Fatal("expected usage error without a project")
+	}
+}
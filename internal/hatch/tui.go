@@ -1,9 +1,11 @@
 package hatch
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
 	"sort"
@@ -28,8 +30,86 @@ const (
 	actionRenameInput
 	actionDuplicateInput
 	actionWorktreeInput
+	actionTagInput
+	actionNoteInput
+	actionArchiveConfirm
+	actionMoveInput
+	actionPromoteInput
 )
 
+// confirms reports whether the action asks y/n instead of taking text.
+func (a browserAction) confirms() bool {
+	return a == actionDeleteConfirm || a == actionArchiveConfirm
+}
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// browserTask is a long-running action (copy, worktree) executed off the
+// Bubble Tea event loop so the browser can render progress and cancel it.
+type browserTask struct {
+	label    string
+	progress string
+	cancel   context.CancelFunc
+	updates  chan string
+	done     chan taskResult
+}
+
+type taskResult struct {
+	status string
+	err    error
+	// marked replaces the browser's marks when set, so a bulk action
+	// keeps the projects it failed on marked.
+	marked map[string]bool
+}
+
+type taskProgressMsg struct {
+	task *browserTask
+	text string
+}
+
+type taskDoneMsg struct {
+	task   *browserTask
+	result taskResult
+}
+
+type spinnerTickMsg struct{}
+
+// statsMsg delivers project stats walked in the background for the
+// modified and size orders.
+type statsMsg struct {
+	stats map[string]projectStats
+}
+
+// report keeps only the latest progress line so a fast producer never
+// blocks on a slow renderer.
+func (t *browserTask) report(text string) {
+	select {
+	case <-t.updates:
+	default:
+	}
+	select {
+	case t.updates <- text:
+	default:
+	}
+}
+
+func waitForTask(task *browserTask) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case text := <-task.updates:
+			return taskProgressMsg{task: task, text: text}
+		case result := <-task.done:
+			return taskDoneMsg{task: task, result: result}
+		}
+	}
+}
+
+func spinnerTick() tea.Cmd {
+	return tea.Tick(progressInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
 type scoredIndex struct {
 	index int
 	score int
@@ -47,6 +127,8 @@ type browserStyles struct {
 	placeholder   lipgloss.Style
 	project       lipgloss.Style
 	projectActive lipgloss.Style
+	chip          lipgloss.Style
+	section       lipgloss.Style
 	empty         lipgloss.Style
 	detail        lipgloss.Style
 	help          lipgloss.Style
@@ -84,6 +166,8 @@ func defaultBrowserStyles() browserStyles {
 		placeholder:   lipgloss.NewStyle().Foreground(neutralPlaceholder),
 		project:       lipgloss.NewStyle().Foreground(neutralText),
 		projectActive: lipgloss.NewStyle().Bold(true).Foreground(selectedFg).Background(selectedBg).Padding(0, 1),
+		chip:          lipgloss.NewStyle().Foreground(accentPeach),
+		section:       lipgloss.NewStyle().Bold(true).Foreground(neutralMuted),
 		empty:         lipgloss.NewStyle().Foreground(neutralMuted),
 		detail:        lipgloss.NewStyle().Foreground(neutralMuted),
 		help:          lipgloss.NewStyle().Foreground(neutralMuted),
@@ -99,9 +183,25 @@ func defaultBrowserStyles() browserStyles {
 }
 
 type browserModel struct {
-	root         string
-	projects     []Project
+	root     string
+	config   config
+	projects []Project
+	// metas holds each listed root's metadata, keyed by root.
+	metas map[string]metadata
+	// spaces are the configured hatcheries to cycle through; space indexes
+	// the one at root unless allSpaces lists them together.
+	spaces       []space
+	space        int
+	allSpaces    bool
+	sort         sortMode
+	stats        map[string]projectStats
+	statsLoading bool
+	// statsPending are projects asked for while a scan was running; they
+	// are scanned when it finishes.
+	statsPending []Project
 	filtered     []int
+	marked       map[string]bool
+	pinnedRows   int
 	cursor       int
 	query        string
 	createInput  string
@@ -111,8 +211,12 @@ type browserModel struct {
 	action       browserAction
 	promptInput  string
 	selectedPath string
+	edit         bool
+	session      bool
 	err          error
 	quitting     bool
+	task         *browserTask
+	spinner      int
 	styles       browserStyles
 	now          func() time.Time
 }
@@ -131,12 +235,56 @@ func newBrowserModelWithClock(root string, projects []Project, now func() time.T
 		status:   "Use arrows to move, Enter to open/create",
 		now:      now,
 	}
+	md, err := loadMetadata(root)
+	if err != nil {
+		m.status = err.Error()
+	}
+	m.metas = map[string]metadata{filepath.Clean(root): md}
+	m.sort, _ = parseSortMode(string(md.Sort))
 	m.refreshFilter()
 	return m
 }
 
 func (m browserModel) Init() tea.Cmd {
-	return nil
+	_, cmd := m.loadStats()
+	return cmd
+}
+
+// loadStats starts walking the projects that have no stats yet when the
+// current order needs them.
+func (m browserModel) loadStats() (browserModel, tea.Cmd) {
+	if !m.sort.needsStats() && !m.config.Browser.Sizes {
+		return m, nil
+	}
+	return m.loadStatsFor(m.projects)
+}
+
+// loadStatsFor starts walking those of projects that have no stats yet.
+func (m browserModel) loadStatsFor(projects []Project) (browserModel, tea.Cmd) {
+	if m.statsLoading {
+		m.statsPending = append(m.statsPending, projects...)
+		return m, nil
+	}
+	// Each root keeps its own size cache.
+	paths := make(map[string][]string)
+	for _, project := range projects {
+		if _, ok := m.stats[project.Path]; !ok {
+			root := filepath.Dir(project.Path)
+			paths[root] = append(paths[root], project.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return m, nil
+	}
+	m.statsLoading = true
+	cached := m.sort != sortModified
+	return m, func() tea.Msg {
+		stats := make(map[string]projectStats)
+		for root, rootPaths := range paths {
+			maps.Copy(stats, scanProjects(root, rootPaths, cached))
+		}
+		return statsMsg{stats: stats}
+	}
 }
 
 func (m browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -145,7 +293,46 @@ func (m browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, nil
+	case taskProgressMsg:
+		if msg.task != m.task {
+			return m, nil
+		}
+		m.task.progress = msg.text
+		return m, waitForTask(m.task)
+	case taskDoneMsg:
+		if msg.task != m.task {
+			return m, nil
+		}
+		return m.finishTask(msg.result)
+	case statsMsg:
+		selected := m.currentProject()
+		if m.stats == nil {
+			m.stats = make(map[string]projectStats, len(msg.stats))
+		}
+		for path, stats := range msg.stats {
+			m.stats[path] = stats
+		}
+		m.statsLoading = false
+		m.refreshFilter()
+		if selected != nil {
+			m.selectProject(selected.Path)
+		}
+		pending := m.statsPending
+		m.statsPending = nil
+		if m.sort.needsStats() || m.config.Browser.Sizes {
+			pending = append(pending, m.projects...)
+		}
+		return m.loadStatsFor(pending)
+	case spinnerTickMsg:
+		if m.task == nil {
+			return m, nil
+		}
+		m.spinner++
+		return m, spinnerTick()
 	case tea.KeyMsg:
+		if m.task != nil {
+			return m.updateTask(msg)
+		}
 		if m.action != actionNone {
 			return m.updateAction(msg)
 		}
@@ -157,9 +344,51 @@ func (m browserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m browserModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
-	case tea.KeyCtrlC, tea.KeyEsc:
+	case tea.KeyEsc:
+		if len(m.marked) > 0 {
+			m.marked = nil
+			m.status = "Cleared marks"
+			return m, nil
+		}
 		m.quitting = true
 		return m, tea.Quit
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyTab:
+		selected := m.currentProject()
+		if selected == nil {
+			return m, nil
+		}
+		if m.marked[selected.Path] {
+			delete(m.marked, selected.Path)
+		} else {
+			if m.marked == nil {
+				m.marked = make(map[string]bool)
+			}
+			m.marked[selected.Path] = true
+		}
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+		}
+		return m, nil
+	case tea.KeyCtrlA:
+		all := len(m.filtered) > 0
+		for _, index := range m.filtered {
+			all = all && m.marked[m.projects[index].Path]
+		}
+		for _, index := range m.filtered {
+			if all {
+				delete(m.marked, m.projects[index].Path)
+				continue
+			}
+			if m.marked == nil {
+				m.marked = make(map[string]bool)
+			}
+			m.marked[m.projects[index].Path] = true
+		}
+		m.status = fmt.Sprintf("%d marked", len(m.marked))
+		return m, nil
 	case tea.KeyUp:
 		if m.cursor > 0 {
 			m.cursor--
@@ -170,13 +399,18 @@ func (m browserModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor++
 		}
 		return m, nil
-	case tea.KeyEnter:
+	case tea.KeyEnter, tea.KeyCtrlE, tea.KeyCtrlT:
+		m.edit = msg.Type == tea.KeyCtrlE
+		m.session = msg.Type == tea.KeyCtrlT
 		if m.isCreateRow(m.cursor) {
 			return m.createFromQuery()
 		}
 		selected := m.currentProject()
 		if selected == nil {
 			m.status = "No matching project"
+			if m.allSpaces && strings.TrimSpace(m.createInput) != "" {
+				m.status = createInSpaceHint
+			}
 			return m, nil
 		}
 		m.selectedPath = selected.Path
@@ -190,11 +424,24 @@ func (m browserModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.status = "Rename selected project"
 		}
 		return m, nil
-	case tea.KeyCtrlW:
-		if m.currentProject() != nil {
-			m.action = actionDeleteConfirm
+	case tea.KeyCtrlW, tea.KeyCtrlX:
+		targets := m.targets()
+		if len(targets) == 0 {
+			return m, nil
+		}
+		m.action = actionDeleteConfirm
+		m.status = "Confirm delete"
+		if msg.Type == tea.KeyCtrlX {
+			m.action = actionArchiveConfirm
+			m.status = "Confirm archive"
+		}
+		m.promptInput = ""
+		return m.loadStatsFor(targets)
+	case tea.KeyCtrlO:
+		if len(m.targets()) > 0 {
+			m.action = actionMoveInput
 			m.promptInput = ""
-			m.status = "Confirm delete"
+			m.status = "Move out of the hatchery"
 		}
 		return m, nil
 	case tea.KeyCtrlV:
@@ -213,6 +460,87 @@ func (m browserModel) updateMain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.status = "Create worktree from selected project"
 		}
 		return m, nil
+	case tea.KeyCtrlP:
+		selected := m.currentProject()
+		if selected == nil {
+			return m, nil
+		}
+		pinned := !m.projectMeta(selected.Path).Pinned
+		if err := setProjectPinned(filepath.Dir(selected.Path), selected.Path, pinned); err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Pinned %s", selected.Name)
+		if !pinned {
+			m.status = fmt.Sprintf("Unpinned %s", selected.Name)
+		}
+		updated, cmd := m.reloadProjects()
+		m = updated.(browserModel)
+		m.selectProject(selected.Path)
+		return m, cmd
+	case tea.KeyCtrlS:
+		selected := m.currentProject()
+		m.sort = m.sort.next()
+		err := updateMetadata(m.root, func(md *metadata) error {
+			md.Sort = m.sort
+			if md.Sort == sortDate {
+				md.Sort = ""
+			}
+			return nil
+		})
+		m.status = "Sorted by " + m.sort.label()
+		if err != nil {
+			m.status = err.Error()
+		}
+		m.refreshFilter()
+		if selected != nil {
+			m.selectProject(selected.Path)
+		}
+		return m.loadStats()
+	case tea.KeyCtrlB:
+		if len(m.spaces) == 0 {
+			m.status = "No spaces configured"
+			return m, nil
+		}
+		switch {
+		case m.allSpaces:
+			m.allSpaces = false
+			m.space = 0
+		case m.space == len(m.spaces)-1:
+			m.allSpaces = true
+		default:
+			m.space++
+		}
+		m.root = m.spaces[m.space].Root
+		m.marked = nil
+		m.cursor = 0
+		m.status = "Space: " + m.spaceLabel()
+		return m.reloadProjects()
+	case tea.KeyCtrlU:
+		if selected := m.currentProject(); selected != nil {
+			m.action = actionPromoteInput
+			m.promptInput, _ = promoteTarget(m.config, *selected, "")
+			m.status = "Promote selected project"
+		}
+		return m, nil
+	case tea.KeyCtrlL:
+		if len(m.marked) > 0 {
+			m.action = actionTagInput
+			m.promptInput = ""
+			m.status = "Edit tags of marked projects"
+		} else if selected := m.currentProject(); selected != nil {
+			m.action = actionTagInput
+			m.promptInput = strings.Join(m.projectMeta(selected.Path).Tags, " ")
+			m.status = "Edit tags of selected project"
+		}
+		return m, nil
+	case tea.KeyCtrlN:
+		if selected := m.currentProject(); selected != nil {
+			m.action = actionNoteInput
+			m.promptInput = m.projectMeta(selected.Path).Note
+			m.status = "Edit note of selected project"
+		}
+		return m, nil
 	case tea.KeyBackspace, tea.KeyDelete:
 		if len(m.query) > 0 {
 			_, size := utf8.DecodeLastRuneInString(m.query)
@@ -253,7 +581,7 @@ func (m browserModel) updateAction(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyEnter:
 		return m.applyAction()
 	case tea.KeyBackspace, tea.KeyDelete:
-		if m.action == actionDeleteConfirm {
+		if m.action.confirms() {
 			return m, nil
 		}
 		if len(m.promptInput) > 0 {
@@ -262,13 +590,13 @@ func (m browserModel) updateAction(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case tea.KeySpace:
-		if m.action != actionDeleteConfirm {
+		if !m.action.confirms() {
 			m.promptInput += " "
 		}
 		return m, nil
 	case tea.KeyRunes:
 		text := strings.ToLower(string(msg.Runes))
-		if m.action == actionDeleteConfirm {
+		if m.action.confirms() {
 			switch text {
 			case "y":
 				return m.applyAction()
@@ -286,6 +614,20 @@ func (m browserModel) updateAction(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m browserModel) applyAction() (tea.Model, tea.Cmd) {
+	if m.action == actionMoveInput && strings.TrimSpace(m.promptInput) == "" {
+		m.status = "Enter a destination directory"
+		return m, nil
+	}
+	bulk := m.action.confirms() || m.action == actionMoveInput || (m.action == actionTagInput && len(m.marked) > 0)
+	if targets := m.targets(); bulk && len(targets) > 0 {
+		if m.action != actionTagInput {
+			return m.startBulk(targets)
+		}
+		m = m.applyTags(targets)
+		m.action = actionNone
+		m.promptInput = ""
+		return m.reloadProjects()
+	}
 	selected := m.currentProject()
 	if selected == nil {
 		m.action = actionNone
@@ -296,17 +638,32 @@ func (m browserModel) applyAction() (tea.Model, tea.Cmd) {
 
 	var err error
 	switch m.action {
-	case actionDeleteConfirm:
-		err = removeProject(selected.Path)
-		if err == nil {
-			m.status = fmt.Sprintf("Deleted %s", selected.Name)
-		}
 	case actionRenameInput:
 		err = m.renameProject(selected, m.promptInput)
 	case actionDuplicateInput:
-		err = m.duplicateProject(selected, m.promptInput)
+		return m.startTask(fmt.Sprintf("Duplicating %s", selected.Name), m.duplicateProject(*selected, m.promptInput))
 	case actionWorktreeInput:
-		err = m.createWorktree(selected, m.promptInput)
+		return m.startTask(fmt.Sprintf("Creating worktree from %s", selected.Name), m.createWorktree(*selected, m.promptInput))
+	case actionTagInput:
+		tags := parseTags(m.promptInput)
+		err = setProjectTags(filepath.Dir(selected.Path), selected.Path, tags)
+		m.status = fmt.Sprintf("Tagged %s: %s", selected.Name, formatTags(tags))
+		if len(tags) == 0 {
+			m.status = fmt.Sprintf("Cleared tags of %s", selected.Name)
+		}
+	case actionPromoteInput:
+		target, err := promoteTarget(m.config, *selected, strings.TrimSpace(m.promptInput))
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		return m.startTask(fmt.Sprintf("Promoting %s", selected.Name), m.promoteProject(*selected, target))
+	case actionNoteInput:
+		err = setProjectNote(filepath.Dir(selected.Path), selected.Path, m.promptInput)
+		m.status = fmt.Sprintf("Noted %s", selected.Name)
+		if strings.TrimSpace(m.promptInput) == "" {
+			m.status = fmt.Sprintf("Cleared note of %s", selected.Name)
+		}
 	}
 	if err != nil {
 		m.status = err.Error()
@@ -327,58 +684,210 @@ func (m browserModel) renameProject(selected *Project, newName string) error {
 	if prefix := datedPrefix(selected.Name); prefix != "" {
 		targetName = prefix + "-" + norm
 	}
-	targetPath := filepath.Join(m.root, targetName)
+	root := filepath.Dir(selected.Path)
+	targetPath := filepath.Join(root, targetName)
 	if targetPath == selected.Path {
 		m.status = "Name unchanged"
 		return nil
 	}
-	if _, err := os.Stat(targetPath); err == nil {
-		return fmt.Errorf("rename failed: project already exists: %s", targetPath)
-	} else if !errors.Is(err, os.ErrNotExist) {
+	err = withHatcheryLock(root, func() error {
+		if _, err := os.Stat(targetPath); err == nil {
+			return fmt.Errorf("project already exists: %s", targetPath)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return os.Rename(selected.Path, targetPath)
+	})
+	if err != nil {
 		return fmt.Errorf("rename failed: %w", err)
 	}
-	if err := os.Rename(selected.Path, targetPath); err != nil {
+	err = updateMetadata(root, func(md *metadata) error {
+		md.moveProject(selected.Path, targetPath)
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("rename failed: %w", err)
 	}
 	m.status = fmt.Sprintf("Renamed %s -> %s", selected.Name, targetName)
 	return nil
 }
 
-func (m browserModel) duplicateProject(selected *Project, newName string) error {
-	target, err := duplicateProjectFn(m.root, selected.Path, newName, m.currentTime())
-	if err != nil {
-		return fmt.Errorf("duplicate failed: %w", err)
+func (m browserModel) duplicateProject(selected Project, newName string) func(context.Context, func(string)) (string, error) {
+	root, cfg, now := filepath.Dir(selected.Path), m.config, m.currentTime()
+	return func(ctx context.Context, report func(string)) (string, error) {
+		opts, err := cfg.copyOptions()
+		if err != nil {
+			return "", fmt.Errorf("duplicate failed: %w", err)
+		}
+		onExists, err := cfg.existsPolicy()
+		if err != nil {
+			return "", fmt.Errorf("duplicate failed: %w", err)
+		}
+		opts.progress = func(progress copyProgress) {
+			report("Copying " + progress.String())
+		}
+		target, result, err := duplicateProjectFn(ctx, root, selected.Path, newName, now, onExists, opts)
+		if err != nil {
+			return "", fmt.Errorf("duplicate failed: %w", err)
+		}
+		status := fmt.Sprintf("Duplicated %s -> %s", selected.Name, filepath.Base(target))
+		if summary := result.summary(); summary != "" {
+			status += " (" + summary + ")"
+		}
+		if result.sharedWarning() != "" {
+			status += "; hardlinked files are shared with the source"
+		}
+		return status, nil
 	}
-	m.status = fmt.Sprintf("Duplicated %s -> %s", selected.Name, filepath.Base(target))
-	return nil
 }
 
-func (m browserModel) createWorktree(selected *Project, newName string) error {
-	target, err := createWorktreeFn(m.root, selected.Path, newName, m.currentTime())
-	if err != nil {
-		return fmt.Errorf("worktree failed: %w", err)
+// promoteProject moves selected to target off the event loop, since a move
+// to another filesystem copies the whole tree.
+func (m browserModel) promoteProject(selected Project, target string) func(context.Context, func(string)) (string, error) {
+	link, now := m.config.Promote.Symlink, m.currentTime()
+	return func(ctx context.Context, report func(string)) (string, error) {
+		var warnings []string
+		opts := moveOptions{
+			progress: func(progress copyProgress) { report("Copying " + progress.String()) },
+			warn:     func(message string) { warnings = append(warnings, message) },
+		}
+		if err := promoteProject(ctx, filepath.Dir(selected.Path), selected, target, link, now, opts); err != nil {
+			return "", err
+		}
+		status := fmt.Sprintf("Promoted %s to %s", selected.Name, target)
+		if len(warnings) > 0 {
+			status += "; " + strings.Join(warnings, "; ")
+		}
+		return status, nil
 	}
-	m.status = fmt.Sprintf("Worktree created %s -> %s", selected.Name, filepath.Base(target))
-	return nil
+}
+
+func (m browserModel) createWorktree(selected Project, newName string) func(context.Context, func(string)) (string, error) {
+	root, cfg, now := filepath.Dir(selected.Path), m.config, m.currentTime()
+	return func(ctx context.Context, report func(string)) (string, error) {
+		onExists, err := cfg.existsPolicy()
+		if err != nil {
+			return "", fmt.Errorf("worktree failed: %w", err)
+		}
+		target, err := createWorktreeFn(ctx, root, selected.Path, newName, now, onExists, &progressLineWriter{report: report})
+		if err != nil {
+			return "", fmt.Errorf("worktree failed: %w", err)
+		}
+		return fmt.Sprintf("Worktree created %s -> %s", selected.Name, filepath.Base(target)), nil
+	}
+}
+
+// startTask runs fn in the background and keeps the current action open
+// until it finishes, so a failure can be corrected and retried.
+func (m browserModel) startTask(label string, fn func(context.Context, func(string)) (string, error)) (tea.Model, tea.Cmd) {
+	return m.runTask(label, func(ctx context.Context, report func(string)) taskResult {
+		status, err := fn(ctx, report)
+		return taskResult{status: status, err: err}
+	})
+}
+
+// runTask is startTask for work that reports a full taskResult.
+func (m browserModel) runTask(label string, fn func(context.Context, func(string)) taskResult) (tea.Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &browserTask{
+		label:   label,
+		cancel:  cancel,
+		updates: make(chan string, 1),
+		done:    make(chan taskResult, 1),
+	}
+	go func() {
+		task.done <- fn(ctx, task.report)
+	}()
+
+	m.task = task
+	m.spinner = 0
+	m.status = label
+	return m, tea.Batch(waitForTask(task), spinnerTick())
+}
+
+func (m browserModel) updateTask(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.task.cancel()
+		m.status = "Cancelling…"
+	}
+	return m, nil
+}
+
+func (m browserModel) finishTask(result taskResult) (tea.Model, tea.Cmd) {
+	m.task.cancel()
+	m.task = nil
+	if errors.Is(result.err, context.Canceled) {
+		m.action = actionNone
+		m.promptInput = ""
+		m.status = "Action cancelled"
+		return m.reloadProjects()
+	}
+	if onExists, err := m.config.existsPolicy(); err == nil {
+		if existing, ok := openExisting(onExists, result.err); ok {
+			m.selectedPath = existing
+			m.quitting = true
+			return m, tea.Quit
+		}
+	}
+	if result.err != nil {
+		m.status = result.err.Error()
+		return m, nil
+	}
+
+	m.action = actionNone
+	m.promptInput = ""
+	m.status = result.status
+	if result.marked != nil {
+		m.marked = result.marked
+	}
+	return m.reloadProjects()
 }
 
 func (m browserModel) reloadProjects() (tea.Model, tea.Cmd) {
-	projects, err := listProjects(m.root)
-	if err != nil {
-		m.err = err
-		m.quitting = true
-		return m, tea.Quit
+	var projects []Project
+	metas := make(map[string]metadata)
+	for _, root := range m.roots() {
+		// Cycling through spaces only looks; a space nobody has created
+		// a project in yet is shown empty rather than created.
+		if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		listed, err := listProjects(root)
+		if err != nil {
+			m.err = err
+			m.quitting = true
+			return m, tea.Quit
+		}
+		md, err := loadMetadata(root)
+		if err != nil {
+			m.err = err
+			m.quitting = true
+			return m, tea.Quit
+		}
+		projects = append(projects, listed...)
+		metas[filepath.Clean(root)] = md
 	}
 	m.projects = projects
+	m.metas = metas
+	for path := range m.marked {
+		if _, err := os.Stat(path); err != nil {
+			delete(m.marked, path)
+		}
+	}
 	m.refreshFilter()
-	return m, nil
+	return m.loadStats()
 }
 
 func (m *browserModel) refreshFilter() {
-	query := strings.TrimSpace(strings.ToLower(m.query))
-	m.createInput = strings.TrimSpace(m.query)
+	tags, text := splitQuery(m.query)
+	query := strings.ToLower(text)
+	m.createInput = text
 	scored := make([]scoredIndex, 0, len(m.projects))
 	for i, project := range m.projects {
+		if !hasTags(m.projectMeta(project.Path).Tags, tags) {
+			continue
+		}
 		score := fuzzyScore(project.Name, query)
 		if score == noMatchScore {
 			continue
@@ -386,16 +895,34 @@ func (m *browserModel) refreshFilter() {
 		scored = append(scored, scoredIndex{index: i, score: score})
 	}
 
+	// With an empty query pinned projects form their own section on top;
+	// while filtering, the best match comes first regardless.
+	pinnedFirst := strings.TrimSpace(m.query) == ""
+	pinned := func(item scoredIndex) bool {
+		return pinnedFirst && m.projectMeta(m.projects[item.index].Path).Pinned
+	}
+	now := m.currentTime()
 	sort.Slice(scored, func(i, j int) bool {
+		if pinned(scored[i]) != pinned(scored[j]) {
+			return pinned(scored[i])
+		}
+		a, b := m.projects[scored[i].index], m.projects[scored[j].index]
+		if m.sort != sortDate {
+			return compareProjects(m.sort, a, b, m.projectMeta(a.Path), m.projectMeta(b.Path), m.stats[a.Path], m.stats[b.Path], now) < 0
+		}
 		if scored[i].score == scored[j].score {
-			return m.projects[scored[i].index].Name > m.projects[scored[j].index].Name
+			return a.Name > b.Name
 		}
 		return scored[i].score > scored[j].score
 	})
 
 	m.filtered = m.filtered[:0]
+	m.pinnedRows = 0
 	for _, item := range scored {
 		m.filtered = append(m.filtered, item.index)
+		if pinned(item) {
+			m.pinnedRows++
+		}
 	}
 
 	if m.cursor >= m.rowCount() {
@@ -403,6 +930,51 @@ func (m *browserModel) refreshFilter() {
 	}
 }
 
+// roots lists the hatchery roots the browser shows.
+func (m browserModel) roots() []string {
+	if !m.allSpaces {
+		return []string{m.root}
+	}
+	roots := make([]string, 0, len(m.spaces))
+	for _, space := range m.spaces {
+		roots = append(roots, space.Root)
+	}
+	return roots
+}
+
+// projectMeta returns the metadata of the project at path from its root.
+func (m browserModel) projectMeta(path string) projectMeta {
+	return m.metas[filepath.Dir(path)].project(path)
+}
+
+// spaceLabel names what the browser shows, such as "work" or "all spaces".
+func (m browserModel) spaceLabel() string {
+	if m.allSpaces {
+		return "all spaces"
+	}
+	return m.spaces[m.space].Name
+}
+
+// spaceOf names the space a project lives in, for the all-spaces column.
+func (m browserModel) spaceOf(path string) string {
+	for _, space := range m.spaces {
+		if filepath.Clean(space.Root) == filepath.Dir(path) {
+			return space.Name
+		}
+	}
+	return ""
+}
+
+// selectProject moves the cursor to the project at path if it is listed.
+func (m *browserModel) selectProject(path string) {
+	for row, index := range m.filtered {
+		if m.projects[index].Path == path {
+			m.cursor = row
+			return
+		}
+	}
+}
+
 func (m browserModel) currentProject() *Project {
 	if len(m.filtered) == 0 || m.cursor < 0 || m.cursor >= len(m.filtered) || m.isCreateRow(m.cursor) {
 		return nil
@@ -418,6 +990,16 @@ func (m browserModel) View() string {
 
 	title := m.styles.title.Render("hatch")
 	subtitle := m.styles.subtitle.Render("Project hatchery")
+	sortLabel := "sort: " + m.sort.label()
+	if m.statsLoading && m.sort.needsStats() {
+		sortLabel += " (scanning…)"
+	}
+	if len(m.marked) > 0 {
+		sortLabel += fmt.Sprintf("  •  %d marked", len(m.marked))
+	}
+	if len(m.spaces) > 0 {
+		sortLabel = "space: " + m.spaceLabel() + "  •  " + sortLabel
+	}
 	searchLabel := m.styles.searchLabel.Render("Filter")
 
 	query := m.styles.placeholder.Render("type to search")
@@ -440,13 +1022,19 @@ func (m browserModel) View() string {
 		}
 	} else if selected := m.currentProject(); selected != nil {
 		selectedInfo = m.styles.detail.Render(selected.Path)
+		if selected.Target != "" {
+			selectedInfo += "\n" + m.styles.detail.Render("→ "+selected.Target)
+		}
+		if note := m.projectMeta(selected.Path).Note; note != "" {
+			selectedInfo += "\n" + m.styles.detail.Render("“"+note+"”")
+		}
 	}
 
-	help := m.styles.help.Render("↑/↓ move  •  type to filter  •  Enter open/create  •  Ctrl+R rename  •  Ctrl+W delete  •  Ctrl+V duplicate  •  Ctrl+G worktree  •  Esc quit")
+	help := m.styles.help.Render("↑/↓ move  •  type to filter  •  Enter open/create  •  Ctrl+R rename  •  Ctrl+W delete  •  Ctrl+X archive  •  Ctrl+O move  •  Ctrl+U promote  •  Tab mark  •  Ctrl+A mark all  •  Ctrl+V duplicate  •  Ctrl+G worktree  •  Ctrl+E edit  •  Ctrl+T session  •  Ctrl+P pin  •  Ctrl+S sort  •  Ctrl+L tags  •  Ctrl+N note  •  Ctrl+B space  •  Esc quit")
 	status := m.styles.status.Render(m.status)
 
 	body := []string{
-		lipgloss.JoinHorizontal(lipgloss.Top, title, "  ", subtitle),
+		lipgloss.JoinHorizontal(lipgloss.Top, title, "  ", subtitle, "  ", m.styles.detail.Render(sortLabel)),
 		"",
 		searchLabel,
 		searchLine,
@@ -460,7 +1048,9 @@ func (m browserModel) View() string {
 
 	body = append(body, "", status, help)
 
-	if m.action != actionNone {
+	if m.task != nil {
+		body = append(body, "", m.taskPrompt(appWidth))
+	} else if m.action != actionNone {
 		body = append(body, "", m.actionPrompt(appWidth))
 	}
 
@@ -471,25 +1061,59 @@ func (m browserModel) View() string {
 	return m.styles.app.Render(content)
 }
 
+// hasSectionHeader reports whether the "Pinned" or "Projects" header is
+// drawn above row.
+func (m browserModel) hasSectionHeader(row int) bool {
+	return m.pinnedRows > 0 && !m.isCreateRow(row) && (row == 0 || row == m.pinnedRows)
+}
+
+// visibleRows returns the rows that fit in budget lines with the cursor
+// in view, counting the section headers drawn above some of them.
+func (m browserModel) visibleRows(budget int) (start, end int) {
+	height := func(row int) int {
+		if m.hasSectionHeader(row) {
+			return 2
+		}
+		return 1
+	}
+	fill := func(from int) int {
+		used, to := 0, from
+		for to < m.rowCount() && used+height(to) <= budget {
+			used += height(to)
+			to++
+		}
+		return to
+	}
+	if end = fill(0); m.cursor < end {
+		return 0, end
+	}
+	// Scroll so the cursor is the last row, then use any room left over.
+	start, used := m.cursor, height(m.cursor)
+	for start > 0 && used+height(start-1) <= budget {
+		start--
+		used += height(start)
+	}
+	return start, fill(start)
+}
+
 func (m browserModel) renderRows() string {
 	totalRows := m.rowCount()
 	if totalRows == 0 {
 		if strings.TrimSpace(m.query) == "" {
 			return m.styles.empty.Render("No projects yet. Run: hatch <name>")
 		}
+		if m.allSpaces {
+			return m.styles.empty.Render("No matches. " + createInSpaceHint)
+		}
 		return m.styles.empty.Render("No matches")
 	}
 
-	maxRows := max(8, m.height-14)
-	if maxRows > totalRows {
-		maxRows = totalRows
-	}
+	start, end := m.visibleRows(max(8, m.height-14))
 
-	start := 0
-	if m.cursor >= maxRows {
-		start = m.cursor - maxRows + 1
+	spaceWidth := 0
+	for _, space := range m.spaces {
+		spaceWidth = max(spaceWidth, utf8.RuneCountInString(space.Name)+2)
 	}
-	end := min(start+maxRows, totalRows)
 
 	lines := make([]string, 0, end-start)
 	for row := start; row < end; row++ {
@@ -504,18 +1128,83 @@ func (m browserModel) renderRows() string {
 			continue
 		}
 
+		if m.hasSectionHeader(row) && row == 0 {
+			lines = append(lines, m.styles.section.Render("Pinned"))
+		} else if m.hasSectionHeader(row) {
+			lines = append(lines, m.styles.section.Render("Projects"))
+		}
+
 		project := m.projects[m.filtered[row]]
-		line := fmt.Sprintf("  %s", project.Name)
+		meta := m.projectMeta(project.Path)
+		chips := formatTags(meta.Tags)
+		if meta.Pinned && m.pinnedRows == 0 {
+			chips = strings.TrimSpace("📌 " + chips)
+		}
+		if value := m.sortValue(project); value != "" {
+			chips = strings.TrimSpace(chips + "  " + value)
+		}
+		name := project.Name
+		if project.Target != "" {
+			name += " ↗"
+		}
+		if len(m.marked) > 0 {
+			mark := "○ "
+			if m.marked[project.Path] {
+				mark = "● "
+			}
+			name = mark + name
+		}
+		if m.config.Browser.Sizes {
+			if stats, ok := m.stats[project.Path]; ok {
+				name = fmt.Sprintf("%-*s", sizeColumnWidth, formatBytes(stats.Size)) + name
+			} else {
+				name = strings.Repeat(" ", sizeColumnWidth) + name
+			}
+		}
+		if m.allSpaces {
+			name = fmt.Sprintf("%-*s", spaceWidth, m.spaceOf(project.Path)) + name
+		}
+		line := fmt.Sprintf("  %s", name)
 		if row == m.cursor {
-			line = m.styles.projectActive.Render("▸ " + project.Name)
+			label := "▸ " + name
+			if chips != "" {
+				label += "  " + chips
+			}
+			line = m.styles.projectActive.Render(label)
 		} else {
 			line = m.styles.project.Render(line)
+			if chips != "" {
+				line += "  " + m.styles.chip.Render(chips)
+			}
 		}
 		lines = append(lines, line)
 	}
 	return strings.Join(lines, "\n")
 }
 
+// sizeColumnWidth fits sizes such as "1023.9 MiB" plus a gap.
+const sizeColumnWidth = 12
+
+// sortValue is what the modified and size orders sort on, shown next to
+// each project.
+func (m browserModel) sortValue(project Project) string {
+	stats, ok := m.stats[project.Path]
+	if !ok {
+		return ""
+	}
+	switch m.sort {
+	case sortModified:
+		return formatAge(stats.Modified, m.currentTime())
+	case sortSize:
+		if m.config.Browser.Sizes {
+			return ""
+		}
+		return formatBytes(stats.Size)
+	default:
+		return ""
+	}
+}
+
 func (m browserModel) rowCount() int {
 	rows := len(m.filtered)
 	if m.hasCreateOption() {
@@ -524,8 +1213,15 @@ func (m browserModel) rowCount() int {
 	return rows
 }
 
+// createInSpaceHint explains why the all-spaces view cannot create the
+// typed project.
+const createInSpaceHint = "Press Ctrl+B to pick a space to create it in"
+
+// hasCreateOption reports whether the list ends in a row that creates the
+// typed project. The all-spaces view has none, since it has no one space
+// to create it in.
 func (m browserModel) hasCreateOption() bool {
-	return strings.TrimSpace(m.createInput) != ""
+	return !m.allSpaces && strings.TrimSpace(m.createInput) != ""
 }
 
 func (m browserModel) isCreateRow(row int) bool {
@@ -546,22 +1242,44 @@ func (m browserModel) createFromQuery() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	projectPath, err := createProject(m.root, name, m.currentTime())
+	onExists, err := m.config.existsPolicy()
+	if err != nil {
+		m.status = fmt.Sprintf("Create failed: %v", err)
+		return m, nil
+	}
+	projectPath, err := createProject(m.root, name, m.currentTime(), onExists)
+	if existing, ok := openExisting(onExists, err); ok {
+		projectPath, err = existing, nil
+	}
 	if err != nil {
 		m.status = fmt.Sprintf("Create failed: %v", err)
 		return m, nil
 	}
+	// "api #auth" creates api and tags it, matching the filter it was
+	// typed into.
+	if tags, _ := splitQuery(m.query); len(tags) > 0 {
+		edits := make([]string, len(tags))
+		for i, tag := range tags {
+			edits[i] = "+" + tag
+		}
+		err = updateMetadata(m.root, func(md *metadata) error {
+			meta := md.project(projectPath)
+			meta.Tags, _ = editTags(meta.Tags, edits)
+			md.setProject(projectPath, meta)
+			return nil
+		})
+		if err != nil {
+			m.status = fmt.Sprintf("Tag failed: %v", err)
+			return m, nil
+		}
+	}
 
 	m.selectedPath = projectPath
 	m.quitting = true
 	return m, tea.Quit
 }
 
-func (m browserModel) actionPrompt(appWidth int) string {
-	selected := m.currentProject()
-	if selected == nil {
-		return ""
-	}
+func (m browserModel) promptBox(appWidth int) lipgloss.Style {
 	boxStyle := m.styles.confirm
 	if appWidth > 0 {
 		// Fit the prompt box exactly inside the app content area:
@@ -569,12 +1287,55 @@ func (m browserModel) actionPrompt(appWidth int) string {
 		contentWidth := appWidth - m.styles.app.GetHorizontalFrameSize() - boxStyle.GetHorizontalFrameSize()
 		boxStyle = boxStyle.Width(max(24, contentWidth))
 	}
+	return boxStyle
+}
+
+func (m browserModel) taskPrompt(appWidth int) string {
+	frame := spinnerFrames[m.spinner%len(spinnerFrames)]
+	msg := m.styles.confirmMsg.Render(frame + " " + m.task.label)
+	progress := m.styles.confirmInput.Render(m.task.progress)
+	actions := m.styles.confirmAction.Render("[Esc] cancel")
+	return m.promptBox(appWidth).Render(strings.Join([]string{msg, progress, "", actions}, "\n"))
+}
+
+func (m browserModel) actionPrompt(appWidth int) string {
+	selected := m.currentProject()
+	if selected == nil && len(m.marked) == 0 {
+		return ""
+	}
+	boxStyle := m.promptBox(appWidth)
 
 	switch m.action {
-	case actionDeleteConfirm:
-		msg := m.styles.confirmMsg.Render(fmt.Sprintf("Delete %s?", selected.Name))
+	case actionDeleteConfirm, actionArchiveConfirm:
+		verb := "Delete"
+		if m.action == actionArchiveConfirm {
+			verb = "Archive"
+		}
+		targets := m.targets()
+		size := "size: scanning…"
+		if total, ok := m.targetSize(targets); ok {
+			size = formatBytes(total)
+		}
+		msg := m.styles.confirmMsg.Render(fmt.Sprintf("%s %s (%s)?", verb, describeTargets(targets), size))
+		notes := []string{m.pinnedNote(targets), m.hiddenNote()}
+		if m.action == actionDeleteConfirm {
+			notes = append(notes, linkNote(targets))
+		}
+		for _, note := range notes {
+			if note != "" {
+				msg += "\n" + m.styles.detail.Render(note)
+			}
+		}
 		actions := m.styles.confirmAction.Render("[y/Enter] confirm  [n/Esc] cancel")
 		return boxStyle.Render(strings.Join([]string{msg, "", actions}, "\n"))
+	case actionMoveInput:
+		msg := m.styles.confirmMsg.Render(fmt.Sprintf("Move %s to directory", describeTargets(m.targets())))
+		if note := m.hiddenNote(); note != "" {
+			msg += "\n" + m.styles.detail.Render(note)
+		}
+		input := m.styles.confirmInput.Render("› " + m.promptInput)
+		actions := m.styles.confirmAction.Render("[Enter] apply  [Esc] cancel")
+		return boxStyle.Render(strings.Join([]string{msg, input, "", actions}, "\n"))
 	case actionRenameInput:
 		msg := m.styles.confirmMsg.Render(fmt.Sprintf("Rename %s (type to edit)", selected.Name))
 		input := m.styles.confirmInput.Render("› " + m.promptInput)
@@ -590,6 +1351,26 @@ func (m browserModel) actionPrompt(appWidth int) string {
 		input := m.styles.confirmInput.Render("› " + m.promptInput)
 		actions := m.styles.confirmAction.Render("[Enter] apply  [Esc] cancel")
 		return boxStyle.Render(strings.Join([]string{msg, input, "", actions}, "\n"))
+	case actionTagInput:
+		var msg string
+		if len(m.marked) > 0 {
+			msg = m.styles.confirmMsg.Render(fmt.Sprintf("Tags for %d projects (+tag adds, -tag removes)", len(m.marked)))
+		} else {
+			msg = m.styles.confirmMsg.Render(fmt.Sprintf("Tags for %s (space separated, empty clears)", selected.Name))
+		}
+		input := m.styles.confirmInput.Render("› " + m.promptInput)
+		actions := m.styles.confirmAction.Render("[Enter] apply  [Esc] cancel")
+		return boxStyle.Render(strings.Join([]string{msg, input, "", actions}, "\n"))
+	case actionPromoteInput:
+		msg := m.styles.confirmMsg.Render(fmt.Sprintf("Promote %s to (type to edit)", selected.Name))
+		input := m.styles.confirmInput.Render("› " + m.promptInput)
+		actions := m.styles.confirmAction.Render("[Enter] apply  [Esc] cancel")
+		return boxStyle.Render(strings.Join([]string{msg, input, "", actions}, "\n"))
+	case actionNoteInput:
+		msg := m.styles.confirmMsg.Render(fmt.Sprintf("Note for %s (empty clears)", selected.Name))
+		input := m.styles.confirmInput.Render("› " + m.promptInput)
+		actions := m.styles.confirmAction.Render("[Enter] apply  [Esc] cancel")
+		return boxStyle.Render(strings.Join([]string{msg, input, "", actions}, "\n"))
 	default:
 		return ""
 	}
@@ -616,30 +1397,80 @@ func (m browserModel) defaultProjectBaseName(name string) string {
 	return name
 }
 
-func runBrowser(root string, in io.Reader, out io.Writer) (string, error) {
+// browserSelection is the project picked in the browser and what to do
+// with it besides entering it.
+type browserSelection struct {
+	Path string
+	// Edit opens the project in an editor.
+	Edit bool
+	// Session opens a tmux or zellij session for the project.
+	Session bool
+}
+
+func runBrowser(root string, cfg config, in io.Reader, out io.Writer) (browserSelection, error) {
 	projects, err := listProjects(root)
 	if err != nil {
-		return "", err
+		return browserSelection{}, err
 	}
 
 	model := newBrowserModel(root, projects)
+	model.config = cfg
+	model.spaces = browserSpaces(cfg, root)
+	for i, space := range model.spaces {
+		if filepath.Clean(space.Root) == filepath.Clean(root) {
+			model.space = i
+		}
+	}
 	program := tea.NewProgram(model, tea.WithInput(in), tea.WithOutput(out))
 	finalModel, err := program.Run()
 	if err != nil {
-		return "", fmt.Errorf("run browser: %w", err)
+		return browserSelection{}, fmt.Errorf("run browser: %w", err)
 	}
 
 	result, ok := finalModel.(browserModel)
 	if !ok {
-		return "", errors.New("unexpected browser model type")
+		return browserSelection{}, errors.New("unexpected browser model type")
 	}
 	if result.err != nil {
-		return "", result.err
+		return browserSelection{}, result.err
 	}
 	if result.selectedPath == "" {
-		return "", errNoSelection
+		return browserSelection{}, errNoSelection
+	}
+	return browserSelection{Path: result.selectedPath, Edit: result.edit, Session: result.session}, nil
+}
+
+// splitQuery separates #tag tokens from the fuzzy part of a browser query.
+func splitQuery(query string) (tags []string, text string) {
+	var words []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "#") {
+			if tag, ok := normalizeTag(field); ok {
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		words = append(words, field)
+	}
+	return tags, strings.Join(words, " ")
+}
+
+// hasTags reports whether every filter is a prefix of one of tags, so
+// #cust already narrows to #customer-x while typing.
+func hasTags(tags, filters []string) bool {
+	for _, filter := range filters {
+		found := false
+		for _, tag := range tags {
+			if strings.HasPrefix(tag, filter) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
 	}
-	return result.selectedPath, nil
+	return true
 }
 
 func fuzzyScore(candidate, query string) int {
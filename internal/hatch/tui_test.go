@@ -2,6 +2,9 @@ package hatch
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -81,7 +84,7 @@ func TestBrowserDeleteAction(t *testing.T) {
 	}
 
 	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
-	model = updated.(browserModel)
+	model = finishTask(t, updated.(browserModel))
 
 	if model.action != actionNone {
 		t.Fatalf("expected actionNone after delete, got %v", model.action)
@@ -147,7 +150,7 @@ func TestBrowserDuplicateAction(t *testing.T) {
 
 	model.promptInput = "hatch-dup"
 	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-	model = updated.(browserModel)
+	model = finishTask(t, updated.(browserModel))
 
 	dupPath := filepath.Join(root, "2026-03-01-hatch-dup")
 	if _, err := os.Stat(dupPath); err != nil {
@@ -174,7 +177,7 @@ func TestBrowserWorktreeAction(t *testing.T) {
 	}
 
 	originalCreateWorktree := createWorktreeFn
-	createWorktreeFn = func(gotRoot, source, name string, now time.Time) (string, error) {
+	createWorktreeFn = func(_ context.Context, gotRoot, source, name string, now time.Time, _ existsPolicy, _ io.Writer) (string, error) {
 		if gotRoot != root {
 			t.Fatalf("worktree root = %q, want %q", gotRoot, root)
 		}
@@ -213,7 +216,7 @@ func TestBrowserWorktreeAction(t *testing.T) {
 
 	model.promptInput = "hatch-wt-test"
 	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
-	model = updated.(browserModel)
+	model = finishTask(t, updated.(browserModel))
 
 	worktreePath := filepath.Join(root, "2026-03-01-hatch-wt-test")
 	if _, err := os.Stat(worktreePath); err != nil {
@@ -221,6 +224,50 @@ func TestBrowserWorktreeAction(t *testing.T) {
 	}
 }
 
+func TestBrowserTaskCancel(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "hatchery")
+	sourcePath := filepath.Join(root, "2026-02-28-hatch")
+	if err := os.MkdirAll(sourcePath, 0o755); err != nil {
+		t.Fatalf("create source project: %v", err)
+	}
+
+	started := make(chan struct{})
+	originalCreateWorktree := createWorktreeFn
+	createWorktreeFn = func(ctx context.Context, _, _, _ string, _ time.Time, _ existsPolicy, progress io.Writer) (string, error) {
+		io.WriteString(progress, "Updating files:  50% (1/2)\r")
+		close(started)
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	t.Cleanup(func() {
+		createWorktreeFn = originalCreateWorktree
+	})
+
+	model := newBrowserModel(root, []Project{{Name: "2026-02-28-hatch", Path: sourcePath}})
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	updated, cmd := updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+	if model.task == nil || cmd == nil {
+		t.Fatalf("expected worktree to run as a background task")
+	}
+
+	<-started
+	updated, _ = model.Update(waitForTask(model.task)())
+	model = updated.(browserModel)
+	if !strings.Contains(model.View(), "Updating files:  50% (1/2)") {
+		t.Fatalf("expected task progress in view, got:\n%s", model.View())
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = finishTask(t, updated.(browserModel))
+	if model.status != "Action cancelled" {
+		t.Fatalf("status = %q, want cancelled", model.status)
+	}
+	if model.action != actionNone {
+		t.Fatalf("expected action to reset after cancel, got %v", model.action)
+	}
+}
+
 func TestRunBrowserSelectsProject(t *testing.T) {
 	t.Parallel()
 
@@ -237,12 +284,12 @@ func TestRunBrowserSelectsProject(t *testing.T) {
 	input := bytes.NewBufferString("beta\r")
 	output := new(bytes.Buffer)
 
-	selected, err := runBrowser(root, input, output)
+	selected, err := runBrowser(root, config{}, input, output)
 	if err != nil {
 		t.Fatalf("runBrowser returned error: %v", err)
 	}
-	if selected != beta {
-		t.Fatalf("selected path = %q, want %q", selected, beta)
+	if selected.Path != beta || selected.Edit {
+		t.Fatalf("selected = %+v, want %q without edit", selected, beta)
 	}
 	if !strings.Contains(output.String(), "\x1b[?25l") {
 		t.Fatalf("expected terminal control output, got %q", output.String())
@@ -265,6 +312,56 @@ func TestBrowserSpaceKeyAppendsToFilter(t *testing.T) {
 	}
 }
 
+func TestBrowserDuplicateOpensExistingProject(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	sourcePath := filepath.Join(root, "2026-02-28-hatch")
+	existing := filepath.Join(root, "2026-02-28-hatch-dup")
+	for _, dir := range []string{sourcePath, existing} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create project %s: %v", dir, err)
+		}
+	}
+
+	model := newBrowserModelWithClock(root, []Project{{Name: "2026-02-28-hatch", Path: sourcePath}}, fixedNow)
+	model.config = config{OnExists: "open"}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlV})
+	model = updated.(browserModel)
+	model.promptInput = "hatch-dup"
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = finishTask(t, updated.(browserModel))
+
+	if model.selectedPath != existing || !model.quitting {
+		t.Fatalf("selected path = %q (quitting=%v), want %q", model.selectedPath, model.quitting, existing)
+	}
+}
+
+func TestBrowserCtrlESelectsProjectForEditing(t *testing.T) {
+	t.Parallel()
+
+	model := newBrowserModel("/tmp", []Project{{Name: "2026-02-28-alpha", Path: "/tmp/2026-02-28-alpha"}})
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	model = updated.(browserModel)
+	if cmd == nil || !model.quitting {
+		t.Fatalf("expected Ctrl+E to quit the browser")
+	}
+	if model.selectedPath != "/tmp/2026-02-28-alpha" || !model.edit {
+		t.Fatalf("selected %q (edit=%v), want alpha for editing", model.selectedPath, model.edit)
+	}
+}
+
+func TestBrowserCtrlTSelectsProjectForSession(t *testing.T) {
+	t.Parallel()
+
+	model := newBrowserModel("/tmp", []Project{{Name: "2026-02-28-alpha", Path: "/tmp/2026-02-28-alpha"}})
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(browserModel)
+	if model.selectedPath != "/tmp/2026-02-28-alpha" || !model.session || model.edit {
+		t.Fatalf("selected %q (session=%v, edit=%v), want alpha for a session", model.selectedPath, model.session, model.edit)
+	}
+}
+
 func TestBrowserCreateNewOptionAndSelect(t *testing.T) {
 	t.Parallel()
 
@@ -322,3 +419,555 @@ func TestBrowserEnterOpensMatchBeforeCreateOption(t *testing.T) {
 		t.Fatalf("selected path = %q, want %q", model.selectedPath, beta)
 	}
 }
+
+// finishTask feeds task messages back into the model until its background
+// task completes.
+func finishTask(t *testing.T, model browserModel) browserModel {
+	t.Helper()
+	for model.task != nil {
+		updated, _ := model.Update(waitForTask(model.task)())
+		model = updated.(browserModel)
+	}
+	return model
+}
+
+func TestBrowserFiltersByTag(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	projects := []Project{
+		{Name: "2026-02-28-api", Path: filepath.Join(root, "2026-02-28-api")},
+		{Name: "2026-02-27-api-v2", Path: filepath.Join(root, "2026-02-27-api-v2")},
+		{Name: "2026-02-26-web", Path: filepath.Join(root, "2026-02-26-web")},
+	}
+	err := updateMetadata(root, func(md *metadata) error {
+		md.setProject(projects[1].Path, projectMeta{Tags: []string{"customer-x", "spike"}})
+		md.setProject(projects[2].Path, projectMeta{Tags: []string{"customer-x"}})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("update metadata: %v", err)
+	}
+
+	model := newBrowserModel(root, projects)
+	names := func(query string) []string {
+		model.query = query
+		model.refreshFilter()
+		var got []string
+		for _, index := range model.filtered {
+			got = append(got, model.projects[index].Name)
+		}
+		return got
+	}
+	if got := strings.Join(names("#customer-x"), ","); got != "2026-02-27-api-v2,2026-02-26-web" {
+		t.Fatalf("#customer-x matched %s", got)
+	}
+	if got := strings.Join(names("#cust api"), ","); got != "2026-02-27-api-v2" {
+		t.Fatalf("#cust api matched %s", got)
+	}
+	if model.createInput != "api" {
+		t.Fatalf("createInput = %q, want the query without tags", model.createInput)
+	}
+	if got := names("#nothing"); len(got) != 0 {
+		t.Fatalf("#nothing matched %v", got)
+	}
+
+	model.query = "#spike"
+	model.refreshFilter()
+	view := model.View()
+	if !strings.Contains(view, "#customer-x #spike") {
+		t.Fatalf("expected tag chips in view:\n%s", view)
+	}
+}
+
+func TestBrowserEditsTagsAndNote(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	path := filepath.Join(root, "2026-02-28-api")
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	model := newBrowserModel(root, []Project{{Name: "2026-02-28-api", Path: path}})
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	model = updated.(browserModel)
+	if model.action != actionTagInput {
+		t.Fatalf("expected actionTagInput, got %v", model.action)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#Auth spike")})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("token refresh repro")})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+
+	md, err := loadMetadata(root)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	meta := md.project(path)
+	if strings.Join(meta.Tags, ",") != "auth,spike" || meta.Note != "token refresh repro" {
+		t.Fatalf("metadata = %+v, want tags auth,spike and the note", meta)
+	}
+	if view := model.View(); !strings.Contains(view, "token refresh repro") {
+		t.Fatalf("expected note in detail line:\n%s", view)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	model = updated.(browserModel)
+	if model.promptInput != "auth spike" {
+		t.Fatalf("tag prompt = %q, want current tags", model.promptInput)
+	}
+}
+
+func TestBrowserRenameAndDeleteKeepMetadataInStep(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	oldPath := filepath.Join(root, "2026-02-28-hatch")
+	if err := os.MkdirAll(oldPath, 0o755); err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+	if err := setProjectTags(root, oldPath, []string{"auth"}); err != nil {
+		t.Fatalf("tag project: %v", err)
+	}
+
+	model := newBrowserModel(root, []Project{{Name: "2026-02-28-hatch", Path: oldPath}})
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	model = updated.(browserModel)
+	model.promptInput = "renamed"
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+
+	md, _ := loadMetadata(root)
+	if tags := md.project(filepath.Join(root, "2026-02-28-renamed")).Tags; len(tags) != 1 || tags[0] != "auth" {
+		t.Fatalf("renamed project tags = %v, want [auth]", tags)
+	}
+	if len(md.Projects) != 1 {
+		t.Fatalf("expected only the renamed entry, got %+v", md.Projects)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	finishTask(t, updated.(browserModel))
+	if md, _ = loadMetadata(root); len(md.Projects) != 0 {
+		t.Fatalf("expected metadata removed with the project, got %+v", md.Projects)
+	}
+}
+
+func TestBrowserCreateFromQueryAppliesTags(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	model := newBrowserModelWithClock(root, nil, fixedNow)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("payments #customer-x")})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+
+	want := filepath.Join(root, "2026-02-28-payments")
+	if model.selectedPath != want {
+		t.Fatalf("selected %q, want %q", model.selectedPath, want)
+	}
+	md, _ := loadMetadata(root)
+	if tags := md.project(want).Tags; len(tags) != 1 || tags[0] != "customer-x" {
+		t.Fatalf("tags = %v, want [customer-x]", tags)
+	}
+}
+
+func TestBrowserPinnedSection(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	var projects []Project
+	for _, name := range []string{"2026-02-28-new", "2026-02-20-mid", "2026-01-02-experiment"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+		projects = append(projects, Project{Name: name, Path: path})
+	}
+
+	model := newBrowserModel(root, projects)
+	model.cursor = 2
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = updated.(browserModel)
+
+	if md, _ := loadMetadata(root); !md.project(projects[2].Path).Pinned {
+		t.Fatal("expected Ctrl+P to pin the project")
+	}
+	if got := model.currentProject(); got == nil || got.Name != "2026-01-02-experiment" || model.cursor != 0 {
+		t.Fatalf("expected the cursor to follow the pinned project to the top, got %v at %d", got, model.cursor)
+	}
+	view := model.View()
+	pinned, rest := strings.Index(view, "Pinned"), strings.Index(view, "Projects")
+	if pinned < 0 || rest < 0 || !(pinned < strings.Index(view, "2026-01-02-experiment") && strings.Index(view, "2026-01-02-experiment") < rest && rest < strings.Index(view, "2026-02-28-new")) {
+		t.Fatalf("expected a pinned section above the other projects:\n%s", view)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2026")})
+	model = updated.(browserModel)
+	if model.projects[model.filtered[0]].Name != "2026-02-28-new" || model.pinnedRows != 0 {
+		t.Fatalf("expected plain match order while filtering:\n%s", model.View())
+	}
+	if !strings.Contains(model.View(), "2026-01-02-experiment  📌") {
+		t.Fatalf("expected a pin marker while filtering:\n%s", model.View())
+	}
+
+	model.query = ""
+	model.refreshFilter()
+	model.cursor = 0
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlP})
+	model = updated.(browserModel)
+	if model.pinnedRows != 0 || !strings.Contains(model.status, "Unpinned") {
+		t.Fatalf("expected Ctrl+P to unpin, status %q", model.status)
+	}
+}
+
+func TestBrowserRowsCountSectionHeaders(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	var projects []Project
+	for day := 20; day >= 1; day-- {
+		name := fmt.Sprintf("2026-02-%02d-p%d", day, day)
+		projects = append(projects, Project{Name: name, Path: filepath.Join(root, name)})
+	}
+	err := updateMetadata(root, func(md *metadata) error {
+		for _, project := range projects[:2] {
+			md.setProject(project.Path, projectMeta{Pinned: true})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("pin projects: %v", err)
+	}
+
+	model := newBrowserModel(root, projects)
+	model.height = 20
+	budget := max(8, model.height-14)
+	for _, cursor := range []int{0, 2, 7, len(projects) - 1} {
+		model.cursor = cursor
+		rows := strings.Split(model.renderRows(), "\n")
+		if len(rows) > budget {
+			t.Fatalf("cursor %d: %d lines, want at most %d:\n%s", cursor, len(rows), budget, strings.Join(rows, "\n"))
+		}
+		if name := model.projects[model.filtered[cursor]].Name; !strings.Contains(strings.Join(rows, "\n"), "▸ "+name) {
+			t.Fatalf("cursor %d on %s scrolled out of view:\n%s", cursor, name, strings.Join(rows, "\n"))
+		}
+	}
+}
+
+func TestBrowserSortModes(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	var projects []Project
+	for _, name := range []string{"2026-02-28-beta", "2026-02-01-alpha", "2026-01-01-gamma"} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatalf("create project: %v", err)
+		}
+		projects = append(projects, Project{Name: name, Path: path})
+	}
+	writeTree(t, projects[2].Path, map[string]string{"big.bin": strings.Repeat("x", 4096)})
+
+	order := func(m browserModel) string {
+		var names []string
+		for _, index := range m.filtered {
+			names = append(names, undatedName(m.projects[index].Name))
+		}
+		return strings.Join(names, ",")
+	}
+
+	model := newBrowserModelWithClock(root, projects, fixedNow)
+	if model.Init() != nil {
+		t.Fatal("expected no background scan in date order")
+	}
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	model = updated.(browserModel)
+	if model.sort != sortModified || cmd == nil || !model.statsLoading {
+		t.Fatalf("expected modified order to start a scan, got %s", model.sort)
+	}
+	if !strings.Contains(model.View(), "sort: last modified (scanning…)") {
+		t.Fatalf("expected sort mode in header:\n%s", model.View())
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(browserModel)
+	if model.statsLoading || model.stats[projects[2].Path].Size != 4096 {
+		t.Fatalf("expected stats to arrive, got %+v", model.stats)
+	}
+
+	updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	model = updated.(browserModel)
+	if model.sort != sortSize || cmd != nil {
+		t.Fatalf("expected size order to reuse stats, got %s", model.sort)
+	}
+	if got := order(model); !strings.HasPrefix(got, "gamma,") {
+		t.Fatalf("size order = %s", got)
+	}
+	if !strings.Contains(model.View(), "4.0 KiB") {
+		t.Fatalf("expected sizes in rows:\n%s", model.View())
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	model = updated.(browserModel)
+	if got := order(model); got != "alpha,beta,gamma" {
+		t.Fatalf("name order = %s", got)
+	}
+
+	reopened := newBrowserModelWithClock(root, projects, fixedNow)
+	if reopened.sort != sortName || order(reopened) != "alpha,beta,gamma" {
+		t.Fatalf("expected the sort mode to persist, got %s", reopened.sort)
+	}
+}
+
+func TestBrowserBulkDeleteShowsCountAndSize(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	var projects []Project
+	for _, name := range []string{"2026-02-28-c", "2026-02-27-b", "2026-02-26-a"} {
+		path := filepath.Join(root, name)
+		writeTree(t, path, map[string]string{"data": strings.Repeat("x", 1024)})
+		projects = append(projects, Project{Name: name, Path: path})
+	}
+
+	model := newBrowserModel(root, projects)
+	for range 2 {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		model = updated.(browserModel)
+	}
+	if len(model.marked) != 2 || model.cursor != 2 {
+		t.Fatalf("expected two marks and the cursor on the third row, got %v at %d", model.marked, model.cursor)
+	}
+
+	updated, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	model = updated.(browserModel)
+	if !strings.Contains(model.View(), "Delete 2 projects (size: scanning…)?") || cmd == nil {
+		t.Fatalf("expected a scanning bulk prompt:\n%s", model.View())
+	}
+	updated, _ = model.Update(cmd())
+	model = updated.(browserModel)
+	if !strings.Contains(model.View(), "Delete 2 projects (2.0 KiB)?") {
+		t.Fatalf("expected count and size in prompt:\n%s", model.View())
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = finishTask(t, updated.(browserModel))
+	if model.status != "Deleted 2 projects" || len(model.marked) != 0 {
+		t.Fatalf("status %q, marks %v", model.status, model.marked)
+	}
+	for i, project := range projects {
+		_, err := os.Stat(project.Path)
+		if exists := err == nil; exists != (i == 2) {
+			t.Fatalf("%s exists = %v", project.Name, exists)
+		}
+	}
+}
+
+func TestBrowserBulkMoveReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	dest := filepath.Join(t.TempDir(), "code")
+	var projects []Project
+	for _, name := range []string{"2026-02-28-b", "2026-02-27-a"} {
+		path := filepath.Join(root, name)
+		writeTree(t, path, map[string]string{"keep": ""})
+		projects = append(projects, Project{Name: name, Path: path})
+	}
+	writeTree(t, filepath.Join(dest, "2026-02-27-a"), map[string]string{"taken": ""})
+
+	model := newBrowserModel(root, projects)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	model = updated.(browserModel)
+	if len(model.marked) != 2 {
+		t.Fatalf("expected Ctrl+A to mark all, got %v", model.marked)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+cleanup")})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(browserModel)
+	md, _ := loadMetadata(root)
+	for _, project := range projects {
+		if tags := md.project(project.Path).Tags; len(tags) != 1 || tags[0] != "cleanup" {
+			t.Fatalf("%s tags = %v", project.Name, tags)
+		}
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	model = updated.(browserModel)
+	model.promptInput = dest
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = finishTask(t, updated.(browserModel))
+
+	if !strings.HasPrefix(model.status, "Moved 1 of 2 projects; 2026-02-27-a: ") {
+		t.Fatalf("status = %q", model.status)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "2026-02-28-b", "keep")); err != nil {
+		t.Fatalf("expected b to be moved: %v", err)
+	}
+	if !model.marked[projects[1].Path] || len(model.marked) != 1 {
+		t.Fatalf("expected only the failed project to stay marked, got %v", model.marked)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(browserModel)
+	if model.quitting || len(model.marked) != 0 {
+		t.Fatal("expected Esc to clear marks before quitting")
+	}
+}
+
+func TestBrowserBulkLeavesHiddenMarksAndFlagsPinned(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	var projects []Project
+	for _, name := range []string{"2026-02-28-api-v2", "2026-02-27-api", "2026-02-26-web"} {
+		path := filepath.Join(root, name)
+		writeTree(t, path, map[string]string{"keep": ""})
+		projects = append(projects, Project{Name: name, Path: path})
+	}
+	if err := updateMetadata(root, func(md *metadata) error {
+		md.setProject(projects[1].Path, projectMeta{Pinned: true})
+		return nil
+	}); err != nil {
+		t.Fatalf("pin project: %v", err)
+	}
+
+	model := newBrowserModel(root, projects)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlA})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("api")})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	model = updated.(browserModel)
+	view := model.View()
+	if !strings.Contains(view, "Delete 2 projects") || !strings.Contains(view, "2026-02-27-api is pinned.") || !strings.Contains(view, "1 marked project hidden by the filter is left out.") {
+		t.Fatalf("expected the prompt to count visible targets and call out pinned and hidden ones:\n%s", view)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = finishTask(t, updated.(browserModel))
+	if _, err := os.Stat(projects[2].Path); err != nil {
+		t.Fatalf("expected the hidden project to be kept: %v", err)
+	}
+	if !model.marked[projects[2].Path] || len(model.marked) != 1 {
+		t.Fatalf("expected the hidden project to stay marked, got %v", model.marked)
+	}
+}
+
+func TestBrowserQueuesStatsWhileScanning(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	var projects []Project
+	for _, name := range []string{"2026-02-28-b", "2026-02-27-a"} {
+		path := filepath.Join(root, name)
+		writeTree(t, path, map[string]string{"data": strings.Repeat("x", 1024)})
+		projects = append(projects, Project{Name: name, Path: path})
+	}
+
+	model := newBrowserModel(root, projects)
+	updated, scan := model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	updated, _ = updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.(browserModel).Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	if scan == nil || cmd != nil {
+		t.Fatal("expected the second prompt to wait for the running scan")
+	}
+
+	updated, cmd = updated.(browserModel).Update(scan())
+	if cmd == nil {
+		t.Fatal("expected the queued project to be scanned after the first scan")
+	}
+	updated, _ = updated.(browserModel).Update(cmd())
+	if view := updated.(browserModel).View(); !strings.Contains(view, "Delete 2026-02-27-a (1.0 KiB)?") {
+		t.Fatalf("expected the queued scan to fill in the size:\n%s", view)
+	}
+}
+
+func TestBrowserSizeColumn(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	path := filepath.Join(root, "2026-02-28-api")
+	writeTree(t, path, map[string]string{"data": strings.Repeat("x", 2048)})
+
+	model := newBrowserModel(root, []Project{{Name: "2026-02-28-api", Path: path}})
+	model.config.Browser.Sizes = true
+	cmd := model.Init()
+	if cmd == nil {
+		t.Fatal("expected the size column to start a scan")
+	}
+	updated, _ := model.Update(cmd())
+	model = updated.(browserModel)
+	if view := model.View(); !strings.Contains(view, "▸ 2.0 KiB     2026-02-28-api") {
+		t.Fatalf("expected a size column:\n%s", view)
+	}
+	if _, err := os.Stat(statsCachePath(root)); err != nil {
+		t.Fatalf("expected sizes to be cached: %v", err)
+	}
+}
+
+func TestBrowserLinkedProjects(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "hatchery")
+	elsewhere := t.TempDir()
+	for _, name := range []string{"site", "tool"} {
+		writeTree(t, filepath.Join(elsewhere, name), map[string]string{"index.html": "keep"})
+	}
+	writeTree(t, filepath.Join(root, "2026-02-28-local"), map[string]string{"a": ""})
+	for name, target := range map[string]string{"2026-02-27-site": "site", "2026-02-26-tool": "tool"} {
+		if err := os.Symlink(filepath.Join(elsewhere, target), filepath.Join(root, name)); err != nil {
+			t.Fatalf("symlink: %v", err)
+		}
+	}
+
+	projects, err := listProjects(root)
+	if err != nil {
+		t.Fatalf("listProjects: %v", err)
+	}
+	if len(projects) != 3 || projects[0].Target != "" || projects[1].Target != linkTarget(filepath.Join(elsewhere, "site")) {
+		t.Fatalf("unexpected projects %+v", projects)
+	}
+
+	model := newBrowserModel(root, projects)
+	if view := model.View(); !strings.Contains(view, "2026-02-27-site ↗") || strings.Contains(view, "2026-02-28-local ↗") {
+		t.Fatalf("expected only linked projects to be marked:\n%s", view)
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model = updated.(browserModel)
+	if view := model.View(); !strings.Contains(view, "→ "+projects[1].Target) {
+		t.Fatalf("expected the link target in the detail line:\n%s", view)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlW})
+	model = updated.(browserModel)
+	if view := model.View(); !strings.Contains(view, "Only the link is removed") {
+		t.Fatalf("expected the delete prompt to mention the link:\n%s", view)
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = finishTask(t, updated.(browserModel))
+	if _, err := os.Lstat(filepath.Join(root, "2026-02-27-site")); !os.IsNotExist(err) {
+		t.Fatalf("expected link to be removed, got %v", err)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	model = updated.(browserModel)
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = finishTask(t, updated.(browserModel))
+	if dest, err := os.Readlink(filepath.Join(root, "archive", "2026-02-26-tool")); err != nil || dest != filepath.Join(elsewhere, "tool") {
+		t.Fatalf("expected the link to be archived, got %q, %v (status %q)", dest, err, model.status)
+	}
+
+	for _, name := range []string{"site", "tool"} {
+		if data, err := os.ReadFile(filepath.Join(elsewhere, name, "index.html")); err != nil || string(data) != "keep" {
+			t.Fatalf("target %s was touched: %q, %v", name, data, err)
+		}
+	}
+}